@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+)
+
+const (
+	auditActionReset         = "reset"
+	auditActionUpgradeUser   = "user.upgrade"
+	auditActionSetRole       = "user.set_role"
+	auditActionSuspendUser   = "user.suspend"
+	auditActionUnsuspendUser = "user.unsuspend"
+	auditActionPutRule       = "moderation_rule.put"
+	auditActionDeleteRule    = "moderation_rule.delete"
+	auditActionResolveReport = "report.resolve"
+	auditActionRemoveChirp   = "chirp.remove"
+
+	auditTargetSystem         = "system"
+	auditTargetUser           = "user"
+	auditTargetModerationRule = "moderation_rule"
+	auditTargetReport         = "report"
+	auditTargetChirp          = "chirp"
+)
+
+// auditEntry describes a privileged change. Before and After are snapshots
+// of the target, with nil standing for one that didn't exist.
+type auditEntry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+// userAuditState is the part of a user that privileged actions change.
+type userAuditState struct {
+	Role             string     `json:"role"`
+	IsChirpyRed      bool       `json:"is_chirpy_red"`
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspendedUntil   *time.Time `json:"suspended_until"`
+	SuspensionReason string     `json:"suspension_reason"`
+}
+
+func userAuditStateFromDB(user database.User) userAuditState {
+	state := userAuditState{
+		Role:             user.Role,
+		IsChirpyRed:      user.IsChirpyRed,
+		SuspensionReason: user.SuspensionReason,
+	}
+	if user.SuspendedAt.Valid {
+		state.SuspendedAt = &user.SuspendedAt.Time
+	}
+	if user.SuspendedUntil.Valid {
+		state.SuspendedUntil = &user.SuspendedUntil.Time
+	}
+	return state
+}
+
+// recordAudit appends an entry to the audit log, attributed to the caller
+// and request in ctx. Pass the queries of the transaction making the change
+// so the entry is only kept if the change is.
+func recordAudit(ctx context.Context, q *database.Queries, entry auditEntry) error {
+	before, err := json.Marshal(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(entry.After)
+	if err != nil {
+		return err
+	}
+
+	return q.CreateAuditLogEntry(ctx, database.CreateAuditLogEntryParams{
+		ActorID:         getViewerID(ctx),
+		Action:          entry.Action,
+		TargetType:      entry.TargetType,
+		TargetID:        entry.TargetID,
+		Before:          before,
+		After:           after,
+		RequestID:       getRequestID(ctx),
+		ClientRequestID: getClientRequestID(ctx),
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+)
+
+// runCommand runs a one-off command given on the command line instead of
+// starting the server.
+func runCommand(ctx context.Context, db *sql.DB, args []string) error {
+	switch args[0] {
+	case "create-admin":
+		return runCreateAdmin(ctx, db, args[1:])
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// runCreateAdmin bootstraps the first admin, either by promoting an existing
+// account or by creating a new one. Once there's an admin, further roles are
+// given out through the admin API instead. The password can come from
+// CHIRPY_ADMIN_PASSWORD to keep it out of the shell history.
+func runCreateAdmin(ctx context.Context, db *sql.DB, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin account")
+	password := flags.String("password", os.Getenv("CHIRPY_ADMIN_PASSWORD"), "password for a new account")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("create-admin: -email is required")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := database.New(tx)
+
+	exists, err := qtx.AdminExists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("create-admin: there's already an admin; use PUT /admin/users/{userID}/role instead")
+	}
+
+	// A new account is recorded with no state before it.
+	var before any
+	user, err := qtx.GetUser(ctx, *email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if *password == "" {
+			return errors.New("create-admin: -password is required to create a new account")
+		}
+
+		hashedPW, err := auth.HashPassword(*password)
+		if err != nil {
+			return err
+		}
+
+		user, err = qtx.CreateUser(ctx, database.CreateUserParams{
+			Email:          *email,
+			HashedPassword: hashedPW,
+		})
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		before = userAuditStateFromDB(user)
+	}
+
+	admin, err := qtx.SetUserRole(ctx, database.SetUserRoleParams{
+		ID:   user.ID,
+		Role: string(auth.RoleAdmin),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = recordAudit(ctx, qtx, auditEntry{
+		Action:     auditActionSetRole,
+		TargetType: auditTargetUser,
+		TargetID:   admin.ID.String(),
+		Before:     before,
+		After:      userAuditStateFromDB(admin),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s (%s) is now an admin\n", user.Email, user.ID)
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+type AuditLogEntry struct {
+	ID              uuid.UUID       `json:"id"`
+	CreatedAt       time.Time       `json:"created_at"`
+	ActorID         *uuid.UUID      `json:"actor_id"`
+	Action          string          `json:"action"`
+	TargetType      string          `json:"target_type"`
+	TargetID        string          `json:"target_id"`
+	Before          json.RawMessage `json:"before"`
+	After           json.RawMessage `json:"after"`
+	RequestID       string          `json:"request_id,omitempty"`
+	ClientRequestID string          `json:"client_request_id,omitempty"`
+}
+
+type auditLogPage struct {
+	Entries    []AuditLogEntry `json:"entries"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func auditLogEntryFromDB(entry database.AuditLog) AuditLogEntry {
+	e := AuditLogEntry{
+		ID:              entry.ID,
+		CreatedAt:       entry.CreatedAt,
+		Action:          entry.Action,
+		TargetType:      entry.TargetType,
+		TargetID:        entry.TargetID,
+		Before:          entry.Before,
+		After:           entry.After,
+		RequestID:       entry.RequestID,
+		ClientRequestID: entry.ClientRequestID,
+	}
+	if entry.ActorID.Valid {
+		e.ActorID = &entry.ActorID.UUID
+	}
+	return e
+}
+
+// parseAuditLogFilters reads the optional actor_id, action, target_type,
+// target_id, since and until query parameters. since and until are RFC 3339
+// times, and the range they cover includes since but not until.
+func parseAuditLogFilters(query url.Values) (database.GetAuditLogParams, error) {
+	var params database.GetAuditLogParams
+
+	if s := query.Get("actor_id"); s != "" {
+		actorID, err := uuid.Parse(s)
+		if err != nil {
+			return params, fmt.Errorf("invalid actor_id: %w", err)
+		}
+		params.ActorID = uuid.NullUUID{UUID: actorID, Valid: true}
+	}
+
+	if s := query.Get("action"); s != "" {
+		params.Action = sql.NullString{String: s, Valid: true}
+	}
+	if s := query.Get("target_type"); s != "" {
+		params.TargetType = sql.NullString{String: s, Valid: true}
+	}
+	if s := query.Get("target_id"); s != "" {
+		params.TargetID = sql.NullString{String: s, Valid: true}
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  *sql.NullTime
+	}{
+		{"since", &params.Since},
+		{"until", &params.Until},
+	} {
+		s := query.Get(bound.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return params, fmt.Errorf("invalid %s: %w", bound.name, err)
+		}
+		*bound.dst = sql.NullTime{Time: t.UTC(), Valid: true}
+	}
+
+	if params.Since.Valid && params.Until.Valid && !params.Since.Time.Before(params.Until.Time) {
+		return params, fmt.Errorf("since must be before until")
+	}
+
+	return params, nil
+}
+
+// handlerGetAuditLog lists audit log entries, most recent first. With
+// ?format=jsonl it instead streams every matching entry as JSON Lines for
+// export, ignoring limit and cursor.
+func (cfg *apiConfig) handlerGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filters, err := parseAuditLogFilters(query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid filter", err)
+		return
+	}
+
+	switch query.Get("format") {
+	case "", "json":
+	case "jsonl":
+		cfg.exportAuditLog(w, r, filters)
+		return
+	default:
+		respondWithError(w, http.StatusBadRequest, "format must be json or jsonl", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	filters.CursorCreatedAt = page.CursorCreatedAt
+	filters.CursorID = page.CursorID
+	filters.Limit = page.Limit + 1
+
+	rows, err := cfg.db.GetAuditLog(r.Context(), filters)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get audit log", err)
+		return
+	}
+
+	entries := []AuditLogEntry{}
+	for _, row := range rows {
+		entries = append(entries, auditLogEntryFromDB(row))
+	}
+
+	entries, next := pagination.Trim(entries, page.Limit, func(e AuditLogEntry) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: e.CreatedAt, ID: e.ID}
+	})
+	respondWithJSON(w, http.StatusOK, auditLogPage{Entries: entries, NextCursor: next})
+}
+
+// exportAuditLog writes every entry matching filters as one JSON object per
+// line, reading the log a page at a time so large exports aren't held in
+// memory.
+func (cfg *apiConfig) exportAuditLog(w http.ResponseWriter, r *http.Request, filters database.GetAuditLogParams) {
+	filters.Limit = pagination.MaxLimit
+
+	rows, err := cfg.db.GetAuditLog(r.Context(), filters)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get audit log", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit_log.jsonl"`)
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for len(rows) > 0 {
+		for _, row := range rows {
+			err = encoder.Encode(auditLogEntryFromDB(row))
+			if err != nil {
+				// The status has already been sent, so all we can do is stop.
+				return
+			}
+		}
+
+		if len(rows) < int(filters.Limit) {
+			return
+		}
+
+		last := rows[len(rows)-1]
+		filters.CursorCreatedAt = sql.NullTime{Time: last.CreatedAt, Valid: true}
+		filters.CursorID = uuid.NullUUID{UUID: last.ID, Valid: true}
+
+		rows, err = cfg.db.GetAuditLog(r.Context(), filters)
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+type Block struct {
+	UserID    uuid.UUID `json:"user_id"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+type blocksPage struct {
+	Users      []Block `json:"users"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type Mute struct {
+	UserID  uuid.UUID `json:"user_id"`
+	MutedAt time.Time `json:"muted_at"`
+}
+
+type mutesPage struct {
+	Users      []Mute `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// handlerBlockUser blocks a user. Neither of them can follow, reply to,
+// mention or like the other while the block lasts, and the blocker's chirps
+// are hidden from the blocked user. Any follows between them are removed.
+func (cfg *apiConfig) handlerBlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	blockedID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	if blockedID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't block yourself", nil)
+		return
+	}
+
+	_, err = cfg.db.GetUserByID(r.Context(), blockedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to block user", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.BlockUser(r.Context(), database.BlockUserParams{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to block user", err)
+		return
+	}
+
+	err = qtx.DeleteFollowsBetween(r.Context(), database.DeleteFollowsBetweenParams{
+		UserID:  userID,
+		OtherID: blockedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to remove follows", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to block user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerUnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	blockedID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	err = cfg.db.UnblockUser(r.Context(), database.UnblockUserParams{
+		BlockerID: userID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unblock user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerGetBlocks lists the users the caller has blocked, most recent first.
+func (cfg *apiConfig) handlerGetBlocks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	rows, err := cfg.db.GetBlocks(r.Context(), database.GetBlocksParams{
+		UserID:          userID,
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get blocked users", err)
+		return
+	}
+
+	blocks := []Block{}
+	for _, row := range rows {
+		blocks = append(blocks, Block{UserID: row.UserID, BlockedAt: row.BlockedAt})
+	}
+
+	blocks, next := pagination.Trim(blocks, page.Limit, func(b Block) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: b.BlockedAt, ID: b.UserID}
+	})
+	respondWithJSON(w, http.StatusOK, blocksPage{Users: blocks, NextCursor: next})
+}
+
+// handlerMuteUser hides a user's chirps from the caller's timeline and their
+// notifications from the caller's notifications. Unlike a block, the muted
+// user isn't told or restricted in any way.
+func (cfg *apiConfig) handlerMuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	mutedID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	if mutedID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't mute yourself", nil)
+		return
+	}
+
+	_, err = cfg.db.GetUserByID(r.Context(), mutedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	err = cfg.db.MuteUser(r.Context(), database.MuteUserParams{
+		MuterID: userID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to mute user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerUnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	mutedID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	err = cfg.db.UnmuteUser(r.Context(), database.UnmuteUserParams{
+		MuterID: userID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unmute user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerGetMutes lists the users the caller has muted, most recent first.
+func (cfg *apiConfig) handlerGetMutes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	rows, err := cfg.db.GetMutes(r.Context(), database.GetMutesParams{
+		UserID:          userID,
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get muted users", err)
+		return
+	}
+
+	mutes := []Mute{}
+	for _, row := range rows {
+		mutes = append(mutes, Mute{UserID: row.UserID, MutedAt: row.MutedAt})
+	}
+
+	mutes, next := pagination.Trim(mutes, page.Limit, func(m Mute) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: m.MutedAt, ID: m.UserID}
+	})
+	respondWithJSON(w, http.StatusOK, mutesPage{Users: mutes, NextCursor: next})
+}
+
+// isBlockedBetween reports whether either user has blocked the other.
+func (cfg *apiConfig) isBlockedBetween(ctx context.Context, userID, otherID uuid.UUID) (bool, error) {
+	return cfg.db.IsBlockedBetween(ctx, database.IsBlockedBetweenParams{
+		UserID:  userID,
+		OtherID: otherID,
+	})
+}
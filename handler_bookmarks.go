@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerBookmarkChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		return
+	}
+
+	err = cfg.db.BookmarkChirp(r.Context(), database.BookmarkChirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to bookmark chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerRemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	err = cfg.db.RemoveBookmark(r.Context(), database.RemoveBookmarkParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to remove bookmark", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerGetBookmarks lists the caller's own bookmarks, most recent first.
+// There's no way to read anyone else's.
+func (cfg *apiConfig) handlerGetBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	rows, err := cfg.db.GetBookmarkedChirps(r.Context(), database.GetBookmarkedChirpsParams{
+		UserID:          userID,
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get bookmarks", err)
+		return
+	}
+
+	rows, next := pagination.Trim(rows, page.Limit, func(row database.GetBookmarkedChirpsRow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: row.BookmarkedAt, ID: row.Chirp.ID}
+	})
+
+	bookmarks := chirpsPage{Chirps: []Chirp{}, NextCursor: next}
+	for _, row := range rows {
+		bookmarks.Chirps = append(bookmarks.Chirps, chirpFromDB(row.Chirp))
+	}
+
+	err = cfg.hydrateChirps(r.Context(), bookmarks.Chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, bookmarks)
+}
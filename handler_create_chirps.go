@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"slices"
-	"strings"
+	"time"
 
+	"github.com/JoeVinten/chirpy/internal/chirptext"
 	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/hashtags"
+	"github.com/JoeVinten/chirpy/internal/mentions"
+	"github.com/google/uuid"
 )
 
 func (cfg *apiConfig) handlerCreateChirp(w http.ResponseWriter, r *http.Request) {
@@ -17,7 +25,14 @@ func (cfg *apiConfig) handlerCreateChirp(w http.ResponseWriter, r *http.Request)
 	}
 
 	type parameters struct {
-		Body string `json:"body"`
+		Body       string      `json:"body"`
+		InReplyTo  *uuid.UUID  `json:"in_reply_to"`
+		RechirpOf  *uuid.UUID  `json:"rechirp_of"`
+		QuoteOf    *uuid.UUID  `json:"quote_of"`
+		MediaIDs   []uuid.UUID `json:"media_ids"`
+		PublishAt  *time.Time  `json:"publish_at"`
+		Poll       *pollInput  `json:"poll"`
+		Visibility string      `json:"visibility"`
 	}
 
 	decoder := json.NewDecoder(r.Body)
@@ -28,49 +43,465 @@ func (cfg *apiConfig) handlerCreateChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	const maxChirpLength = 140
+	if params.RechirpOf != nil {
+		if params.Body != "" || params.InReplyTo != nil || params.QuoteOf != nil || len(params.MediaIDs) > 0 || params.PublishAt != nil || params.Poll != nil || params.Visibility != "" {
+			respondWithError(w, http.StatusBadRequest, "A rechirp can't set anything but rechirp_of", nil)
+			return
+		}
+		cfg.createRechirp(w, r, userID, *params.RechirpOf)
+		return
+	}
 
-	if len(params.Body) >= maxChirpLength {
-		respondWithError(w, http.StatusBadRequest, "Chirp is too long", nil)
+	prepared, ok := cfg.prepareChirp(w, r, userID, chirpInput{
+		Body:       params.Body,
+		InReplyTo:  params.InReplyTo,
+		QuoteOf:    params.QuoteOf,
+		MediaIDs:   params.MediaIDs,
+		PublishAt:  params.PublishAt,
+		Poll:       params.Poll,
+		Visibility: params.Visibility,
+	})
+	if !ok {
 		return
 	}
 
-	cleanParams := database.CreateChirpParams{
-		Body:   profanityFilter(params.Body),
-		UserID: userID,
+	chirp, err := cfg.insertChirp(r.Context(), prepared)
+
+	if err != nil {
+		if errors.Is(err, errInvalidMedia) {
+			respondWithError(w, http.StatusBadRequest, "Invalid media", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Error creating chirp in database", err)
+		return
 	}
 
-	chirp, err := cfg.db.CreateChirp(r.Context(), cleanParams)
+	cfg.respondWithCreatedChirp(w, r, chirp)
+}
+
+// chirpInput is what a client supplies for a new chirp, whether it's posted
+// directly or published from a draft.
+type chirpInput struct {
+	Body       string
+	InReplyTo  *uuid.UUID
+	QuoteOf    *uuid.UUID
+	MediaIDs   []uuid.UUID
+	PublishAt  *time.Time
+	Poll       *pollInput
+	Visibility string
+}
+
+// preparedChirp is a validated chirp that's ready to be stored.
+type preparedChirp struct {
+	params       database.CreateChirpParams
+	mediaIDs     []uuid.UUID
+	poll         *preparedPoll
+	flaggedWords []string
+}
+
+// prepareChirp validates a new chirp, resolves what it replies to or quotes
+// and filters its body. It responds with an error and returns false if the
+// chirp can't be posted.
+func (cfg *apiConfig) prepareChirp(w http.ResponseWriter, r *http.Request, userID uuid.UUID, input chirpInput) (preparedChirp, bool) {
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return preparedChirp{}, false
+	}
 
+	// Only the author's own text is validated; a quoted chirp was checked when it was created.
+	err = validateChirpBody(input.Body, cfg.maxChirpLengthFor(user))
 	if err != nil {
+		respondWithInvalidChirp(w, err)
+		return preparedChirp{}, false
+	}
+
+	verdict := cfg.moderator.Check(input.Body)
+	if verdict.IsBlocked() {
+		respondWithError(w, http.StatusBadRequest, "Chirp contains a blocked word", nil)
+		return preparedChirp{}, false
+	}
+
+	visibility, err := parseVisibility(input.Visibility)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid visibility", err)
+		return preparedChirp{}, false
+	}
+
+	if len(input.MediaIDs) > maxMediaPerChirp {
+		respondWithError(w, http.StatusBadRequest, "A chirp can have at most 4 images", nil)
+		return preparedChirp{}, false
+	}
+	for i, id := range input.MediaIDs {
+		if slices.Contains(input.MediaIDs[:i], id) {
+			respondWithError(w, http.StatusBadRequest, "Duplicate media ID", nil)
+			return preparedChirp{}, false
+		}
+	}
+
+	var scheduledFor sql.NullTime
+	if input.PublishAt != nil {
+		// Scheduled chirps would bump reply and quote counts while still
+		// hidden, so only standalone chirps can be scheduled.
+		if input.InReplyTo != nil || input.QuoteOf != nil {
+			respondWithError(w, http.StatusBadRequest, "Replies and quotes can't be scheduled", nil)
+			return preparedChirp{}, false
+		}
+		scheduledFor, err = parsePublishAt(*input.PublishAt)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid publish time", err)
+			return preparedChirp{}, false
+		}
+	}
+
+	flaggedWords := verdict.Flagged
+	var poll *preparedPoll
+	if input.Poll != nil {
+		// Poll options are shown as publicly as the body, so they're held to
+		// the same word list.
+		pollInput := *input.Poll
+		pollInput.Options = make([]string, len(input.Poll.Options))
+		for i, option := range input.Poll.Options {
+			optionVerdict := cfg.moderator.Check(option)
+			if optionVerdict.IsBlocked() {
+				respondWithError(w, http.StatusBadRequest, "Poll option contains a blocked word", nil)
+				return preparedChirp{}, false
+			}
+			pollInput.Options[i] = optionVerdict.Text
+			for _, word := range optionVerdict.Flagged {
+				if !slices.Contains(flaggedWords, word) {
+					flaggedWords = append(flaggedWords, word)
+				}
+			}
+		}
+
+		opensAt := time.Now()
+		if scheduledFor.Valid {
+			opensAt = scheduledFor.Time
+		}
+		poll, err = preparePoll(pollInput, opensAt)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid poll", err)
+			return preparedChirp{}, false
+		}
+	}
+
+	var inReplyTo uuid.NullUUID
+	if input.InReplyTo != nil {
+		parent, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+			ID:       *input.InReplyTo,
+			ViewerID: getViewerID(r.Context()),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Chirp being replied to doesn't exist", err)
+				return preparedChirp{}, false
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp being replied to", err)
+			return preparedChirp{}, false
+		}
+
+		blocked, err := cfg.isBlockedBetween(r.Context(), userID, parent.UserID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check blocks", err)
+			return preparedChirp{}, false
+		}
+		if blocked {
+			respondWithError(w, http.StatusForbidden, "You can't reply to this chirp", nil)
+			return preparedChirp{}, false
+		}
+		inReplyTo = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
+	var quoteOf uuid.NullUUID
+	if input.QuoteOf != nil {
+		quoteOf, err = cfg.resolveSharedChirp(r.Context(), *input.QuoteOf)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Chirp being quoted doesn't exist", err)
+				return preparedChirp{}, false
+			}
+			if errors.Is(err, errNotShareable) {
+				respondWithError(w, http.StatusBadRequest, "Can't quote that chirp", err)
+				return preparedChirp{}, false
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp being quoted", err)
+			return preparedChirp{}, false
+		}
+	}
+
+	return preparedChirp{
+		params: database.CreateChirpParams{
+			Body:         verdict.Text,
+			UserID:       userID,
+			InReplyTo:    inReplyTo,
+			QuoteOf:      quoteOf,
+			ScheduledFor: scheduledFor,
+			Visibility:   visibility,
+		},
+		mediaIDs:     input.MediaIDs,
+		poll:         poll,
+		flaggedWords: flaggedWords,
+	}, true
+}
+
+func (cfg *apiConfig) createRechirp(w http.ResponseWriter, r *http.Request, userID, chirpID uuid.UUID) {
+	rechirpOf, err := cfg.resolveSharedChirp(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Chirp being rechirped doesn't exist", err)
+			return
+		}
+		if errors.Is(err, errNotShareable) {
+			respondWithError(w, http.StatusBadRequest, "Can't rechirp that chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp being rechirped", err)
+		return
+	}
+
+	chirp, err := cfg.insertChirp(r.Context(), preparedChirp{
+		params: database.CreateChirpParams{
+			UserID:     userID,
+			RechirpOf:  rechirpOf,
+			Visibility: visibilityPublic,
+		},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "You've already rechirped that chirp", err)
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "Error creating chirp in database", err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, Chirp{
-		ID:        chirp.ID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
-	},
-	)
+	cfg.respondWithCreatedChirp(w, r, chirp)
 }
 
-func profanityFilter(t string) string {
-	profanity := []string{"kerfuffle", "sharbert", "fornax"}
-	const filter = "****"
+// insertChirp stores a new chirp along with its attached media, its poll and
+// everything indexed from its body in a single transaction.
+func (cfg *apiConfig) insertChirp(ctx context.Context, prepared preparedChirp) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+
+	chirp, err := storeChirp(ctx, cfg.db.WithTx(tx), prepared)
+	if err != nil {
+		return database.Chirp{}, err
+	}
 
-	cleanBody := []string{}
+	return chirp, tx.Commit()
+}
 
-	for _, word := range strings.Split(t, " ") {
-		if slices.Contains(profanity, strings.ToLower(word)) {
-			cleanBody = append(cleanBody, filter)
-		} else {
-			cleanBody = append(cleanBody, word)
+// storeChirp does the work of insertChirp using q, so callers can make it
+// part of a larger transaction.
+func storeChirp(ctx context.Context, q *database.Queries, prepared preparedChirp) (database.Chirp, error) {
+	chirp, err := q.CreateChirp(ctx, prepared.params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	if len(prepared.mediaIDs) > 0 {
+		attached, err := q.AttachMedia(ctx, database.AttachMediaParams{
+			ChirpID: uuid.NullUUID{UUID: chirp.ID, Valid: true},
+			Ids:     prepared.mediaIDs,
+			UserID:  chirp.UserID,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		if attached != int64(len(prepared.mediaIDs)) {
+			return database.Chirp{}, errInvalidMedia
+		}
+	}
+
+	if prepared.poll != nil {
+		err = storePoll(ctx, q, chirp.ID, *prepared.poll)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	if len(prepared.flaggedWords) > 0 {
+		err = q.FlagChirp(ctx, database.FlagChirpParams{
+			ChirpID: chirp.ID,
+			Words:   prepared.flaggedWords,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	// Scheduled chirps are indexed when they're published, so nobody is
+	// notified about a chirp they can't see yet.
+	if !chirp.ScheduledFor.Valid {
+		err = indexChirpBody(ctx, q, chirp)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	return chirp, nil
+}
+
+// indexChirpBody replaces the hashtags and mentions recorded for a chirp with
+// the ones in its current body, and notifies anyone newly mentioned.
+func indexChirpBody(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+	err := q.ClearChirpHashtags(ctx, chirp.ID)
+	if err != nil {
+		return err
+	}
+
+	// Tags are dated by when the chirp was posted, so editing an old chirp
+	// doesn't count its tags toward trending again.
+	tags := hashtags.Extract(chirp.Body)
+	if len(tags) > 0 {
+		err = q.AddChirpHashtags(ctx, database.AddChirpHashtagsParams{
+			Tags:    tags,
+			ChirpID: chirp.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = q.ClearChirpMentions(ctx, chirp.ID)
+	if err != nil {
+		return err
+	}
+
+	handles := mentions.Extract(chirp.Body)
+	if len(handles) == 0 {
+		return nil
+	}
+
+	mentionedIDs, err := q.AddChirpMentions(ctx, database.AddChirpMentionsParams{
+		ChirpID: chirp.ID,
+		Handles: handles,
+	})
+	if err != nil {
+		return err
+	}
+
+	recipients := []uuid.UUID{}
+	for _, id := range mentionedIDs {
+		if id != chirp.UserID {
+			recipients = append(recipients, id)
 		}
 	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	// Mentioning someone who can't read the chirp, like a non-follower in
+	// a followers-only chirp, shouldn't tell them it exists.
+	recipients, err = q.GetChirpViewers(ctx, database.GetChirpViewersParams{
+		UserIds: recipients,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	// Notifications are unique per chirp, so re-indexing an edit doesn't
+	// notify the same person twice.
+	return q.CreateNotifications(ctx, database.CreateNotificationsParams{
+		ActorID: chirp.UserID,
+		ChirpID: uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		Kind:    notificationKindMention,
+		UserIds: recipients,
+	})
+}
+
+// resolveSharedChirp returns the chirp a rechirp or quote should point at.
+// Sharing a rechirp shares the chirp it points to, so chains never form.
+// Only public chirps can be shared, so a share never reaches readers the
+// author didn't choose.
+func (cfg *apiConfig) resolveSharedChirp(ctx context.Context, chirpID uuid.UUID) (uuid.NullUUID, error) {
+	chirp, err := cfg.db.GetChirp(ctx, database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(ctx),
+	})
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	if chirp.Visibility != visibilityPublic {
+		return uuid.NullUUID{}, errNotShareable
+	}
+	if chirp.RechirpOf.Valid {
+		return chirp.RechirpOf, nil
+	}
+	return uuid.NullUUID{UUID: chirp.ID, Valid: true}, nil
+}
+
+func (cfg *apiConfig) respondWithCreatedChirp(w http.ResponseWriter, r *http.Request, chirp database.Chirp) {
+	chirps := []Chirp{chirpFromDB(chirp)}
+	err := cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, chirps[0])
+}
+
+// Chirps can be up to maxChirpLength characters long. Chirpy Red members get
+// more room, which CHIRPY_RED_MAX_CHIRP_LENGTH can change.
+const (
+	maxChirpLength           = 140
+	defaultMaxChirpLengthRed = 280
+)
+
+// chirpTooLongError reports a chirp body's length as counted by
+// chirptext.Length, along with the most the author is allowed.
+type chirpTooLongError struct {
+	length    int
+	maxLength int
+}
+
+func (e chirpTooLongError) Error() string {
+	return fmt.Sprintf("chirp is %d characters long, the maximum is %d", e.length, e.maxLength)
+}
+
+func validateChirpBody(body string, maxLength int) error {
+	length := chirptext.Length(body)
+	if length > maxLength {
+		return chirpTooLongError{length: length, maxLength: maxLength}
+	}
+	return nil
+}
+
+// respondWithInvalidChirp responds to a body validateChirpBody rejected,
+// including the computed length and the maximum when it's too long.
+func respondWithInvalidChirp(w http.ResponseWriter, err error) {
+	var tooLong chirpTooLongError
+	if !errors.As(err, &tooLong) {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp", err)
+		return
+	}
 
-	return strings.Join(cleanBody, " ")
+	type errorValue struct {
+		Error     string `json:"error"`
+		Length    int    `json:"length"`
+		MaxLength int    `json:"max_length"`
+	}
+	respondWithJSON(w, http.StatusBadRequest, errorValue{
+		Error:     fmt.Sprintf("Invalid chirp: %v", err),
+		Length:    tooLong.length,
+		MaxLength: tooLong.maxLength,
+	})
+}
 
+// maxChirpLengthFor returns how long the user's chirps can be.
+func (cfg *apiConfig) maxChirpLengthFor(user database.User) int {
+	if user.IsChirpyRed {
+		return cfg.maxChirpLengthRed
+	}
+	return maxChirpLength
 }
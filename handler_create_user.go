@@ -1,17 +1,20 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 
 	"github.com/JoeVinten/chirpy/internal/auth"
 	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/mentions"
 )
 
 func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Handle   string `json:"handle"`
 	}
 	type response struct {
 		User
@@ -30,6 +33,16 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	var handle sql.NullString
+	if params.Handle != "" {
+		normalized, err := mentions.NormalizeHandle(params.Handle)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid handle", err)
+			return
+		}
+		handle = sql.NullString{String: normalized, Valid: true}
+	}
+
 	hashedPW, err := auth.HashPassword(params.Password)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "error hashing the password", err)
@@ -39,9 +52,14 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 	user, err := cfg.db.CreateUser(r.Context(), database.CreateUserParams{
 		Email:          params.Email,
 		HashedPassword: hashedPW,
+		Handle:         handle,
 	})
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "email or handle already in use", err)
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "error creating user", err)
 		return
 	}
@@ -53,6 +71,11 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, r *http.Request)
 			UpdatedAt:   user.UpdatedAt,
 			Email:       user.Email,
 			IsChirpyRed: user.IsChirpyRed,
+			Handle:      user.Handle.String,
+			DisplayName: user.DisplayName,
+			Bio:         user.Bio,
+			AvatarURL:   user.AvatarUrl,
+			Role:        user.Role,
 		},
 	})
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/JoeVinten/chirpy/internal/database"
@@ -16,7 +17,10 @@ func (cfg *apiConfig) handlerDeleteChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	chirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
 	if err != nil {
 		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
 		return
@@ -33,10 +37,7 @@ func (cfg *apiConfig) handlerDeleteChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = cfg.db.DeleteChirp(r.Context(), database.DeleteChirpParams{
-		ID:     chirp.ID,
-		UserID: userID,
-	})
+	err = cfg.removeChirp(r.Context(), chirp)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Failed to delete chirp", err)
 		return
@@ -45,3 +46,72 @@ func (cfg *apiConfig) handlerDeleteChirp(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 
 }
+
+// removeChirp deletes a chirp and its media. Chirps with replies are
+// tombstoned instead so the thread below them stays intact, and so are
+// reported chirps so their reports and how they were resolved survive.
+func (cfg *apiConfig) removeChirp(ctx context.Context, chirp database.Chirp) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	blobs, err := deleteChirpRows(ctx, cfg.db.WithTx(tx), chirp)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	cfg.deleteChirpBlobs(ctx, blobs)
+	return nil
+}
+
+// deleteChirpRows does the database side of removeChirp using q, so callers
+// can make it part of a larger transaction. Once that commits, the returned
+// media should be passed to deleteChirpBlobs.
+func deleteChirpRows(ctx context.Context, q *database.Queries, chirp database.Chirp) ([]database.DeleteChirpMediaRow, error) {
+	blobs, err := q.DeleteChirpMedia(ctx, uuid.NullUUID{UUID: chirp.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// reply_count leaves out tombstoned replies, which still hang off the
+	// chirp, so check for replies of any kind.
+	hasReplies, err := q.ChirpHasReplies(ctx, uuid.NullUUID{UUID: chirp.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	reported, err := q.ChirpHasReports(ctx, uuid.NullUUID{UUID: chirp.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if hasReplies || reported {
+		err = q.TombstoneChirp(ctx, database.TombstoneChirpParams{
+			ID:     chirp.ID,
+			UserID: chirp.UserID,
+		})
+	} else {
+		err = q.DeleteChirp(ctx, database.DeleteChirpParams{
+			ID:     chirp.ID,
+			UserID: chirp.UserID,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return blobs, nil
+}
+
+func (cfg *apiConfig) deleteChirpBlobs(ctx context.Context, blobs []database.DeleteChirpMediaRow) {
+	for _, blob := range blobs {
+		cfg.deleteBlobs(ctx, blob.BlobKey, blob.ThumbnailKey)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+)
+
+func TestDeleteReplyWithRepliesUpdatesReplyCount(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, replierToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	parent := createTestChirp(t, cfg, authorToken, map[string]any{"body": "parent"})
+	reply := createTestChirp(t, cfg, replierToken, map[string]any{"body": "reply", "in_reply_to": parent.ID})
+	createTestChirp(t, cfg, authorToken, map[string]any{"body": "reply to the reply", "in_reply_to": reply.ID})
+
+	// The reply has a reply of its own, so it's tombstoned rather than deleted.
+	rec := serveTestRequest(t, "DELETE /api/chirps/{chirpID}", cfg.middlewareAuth(cfg.handlerDeleteChirp),
+		"/api/chirps/"+reply.ID.String(), replierToken, nil)
+	expectStatus(t, rec, http.StatusNoContent, nil)
+
+	rec = serveTestRequest(t, "GET /api/chirps/{chirpID}", cfg.middlewareOptionalAuth(cfg.handlerGetChirp),
+		"/api/chirps/"+parent.ID.String(), "", nil)
+	var got Chirp
+	expectStatus(t, rec, http.StatusOK, &got)
+	if got.ReplyCount != 0 {
+		t.Errorf("reply_count = %d after deleting the only reply, want 0", got.ReplyCount)
+	}
+}
+
+func TestTombstoneChirpChecksOwner(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	other, _ := createTestUser(t, cfg, "", auth.RoleUser)
+
+	chirp := createTestChirp(t, cfg, authorToken, map[string]any{"body": "hello #golang"})
+
+	err := cfg.db.TombstoneChirp(t.Context(), database.TombstoneChirpParams{
+		ID:     chirp.ID,
+		UserID: other.ID,
+	})
+	if err != nil {
+		t.Fatalf("tombstoning chirp: %v", err)
+	}
+
+	rec := serveTestRequest(t, "GET /api/hashtags/{tag}/chirps", cfg.middlewareOptionalAuth(cfg.handlerGetHashtagChirps),
+		"/api/hashtags/golang/chirps", "", nil)
+	var page chirpsPage
+	expectStatus(t, rec, http.StatusOK, &page)
+	if len(page.Chirps) != 1 || page.Chirps[0].ID != chirp.ID {
+		t.Errorf("hashtag chirps = %+v, want the chirp someone else failed to delete", page.Chirps)
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+type Draft struct {
+	ID         uuid.UUID     `json:"id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	Body       string        `json:"body"`
+	InReplyTo  uuid.NullUUID `json:"in_reply_to"`
+	QuoteOf    uuid.NullUUID `json:"quote_of"`
+	MediaIDs   []uuid.UUID   `json:"media_ids"`
+	Visibility string        `json:"visibility"`
+	PublishAt  *time.Time    `json:"publish_at"`
+	Poll       *pollInput    `json:"poll"`
+}
+
+type draftsPage struct {
+	Drafts     []Draft `json:"drafts"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// draftParameters is the request body for creating or replacing a draft.
+// Drafts are only validated when they're published, so work in progress
+// can be saved as-is.
+type draftParameters struct {
+	Body       string      `json:"body"`
+	InReplyTo  *uuid.UUID  `json:"in_reply_to"`
+	QuoteOf    *uuid.UUID  `json:"quote_of"`
+	MediaIDs   []uuid.UUID `json:"media_ids"`
+	Visibility string      `json:"visibility"`
+	PublishAt  *time.Time  `json:"publish_at"`
+	Poll       *pollInput  `json:"poll"`
+}
+
+func draftFromDB(draft database.Draft) Draft {
+	mediaIDs := draft.MediaIds
+	if mediaIDs == nil {
+		mediaIDs = []uuid.UUID{}
+	}
+	return Draft{
+		ID:         draft.ID,
+		CreatedAt:  draft.CreatedAt,
+		UpdatedAt:  draft.UpdatedAt,
+		Body:       draft.Body,
+		InReplyTo:  draft.InReplyTo,
+		QuoteOf:    draft.QuoteOf,
+		MediaIDs:   mediaIDs,
+		Visibility: draft.Visibility,
+		PublishAt:  ptrFromNullTime(draft.PublishAt),
+		Poll:       draftPoll(draft),
+	}
+}
+
+// draftPoll returns the poll saved with a draft, or nil if it has none.
+func draftPoll(draft database.Draft) *pollInput {
+	if !draft.PollClosesAt.Valid {
+		return nil
+	}
+	options := draft.PollOptions
+	if options == nil {
+		options = []string{}
+	}
+	return &pollInput{
+		Options:  options,
+		ClosesAt: draft.PollClosesAt.Time,
+	}
+}
+
+func nullTimeFromPtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func ptrFromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// draftPollColumns splits a draft's poll into the columns it's stored in.
+func draftPollColumns(poll *pollInput) ([]string, sql.NullTime) {
+	if poll == nil {
+		return []string{}, sql.NullTime{}
+	}
+	options := poll.Options
+	if options == nil {
+		options = []string{}
+	}
+	return options, sql.NullTime{Time: poll.ClosesAt, Valid: true}
+}
+
+func nullUUIDFromPtr(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func ptrFromNullUUID(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func decodeDraftParameters(w http.ResponseWriter, r *http.Request) (draftParameters, bool) {
+	decoder := json.NewDecoder(r.Body)
+	params := draftParameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return draftParameters{}, false
+	}
+
+	if len(params.MediaIDs) > maxMediaPerChirp {
+		respondWithError(w, http.StatusBadRequest, "A chirp can have at most 4 images", nil)
+		return draftParameters{}, false
+	}
+	if params.MediaIDs == nil {
+		params.MediaIDs = []uuid.UUID{}
+	}
+
+	return params, true
+}
+
+func (cfg *apiConfig) handlerCreateDraft(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	params, ok := decodeDraftParameters(w, r)
+	if !ok {
+		return
+	}
+
+	pollOptions, pollClosesAt := draftPollColumns(params.Poll)
+	draft, err := cfg.db.CreateDraft(r.Context(), database.CreateDraftParams{
+		UserID:       userID,
+		Body:         params.Body,
+		InReplyTo:    nullUUIDFromPtr(params.InReplyTo),
+		QuoteOf:      nullUUIDFromPtr(params.QuoteOf),
+		MediaIds:     params.MediaIDs,
+		Visibility:   params.Visibility,
+		PublishAt:    nullTimeFromPtr(params.PublishAt),
+		PollOptions:  pollOptions,
+		PollClosesAt: pollClosesAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create draft", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) handlerGetDrafts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	rows, err := cfg.db.GetDrafts(r.Context(), database.GetDraftsParams{
+		UserID:          userID,
+		CursorUpdatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get drafts", err)
+		return
+	}
+
+	rows, next := pagination.Trim(rows, page.Limit, func(d database.Draft) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: d.UpdatedAt, ID: d.ID}
+	})
+
+	drafts := draftsPage{Drafts: []Draft{}, NextCursor: next}
+	for _, row := range rows {
+		drafts.Drafts = append(drafts.Drafts, draftFromDB(row))
+	}
+
+	respondWithJSON(w, http.StatusOK, drafts)
+}
+
+func (cfg *apiConfig) handlerGetDraft(w http.ResponseWriter, r *http.Request) {
+	draft, ok := cfg.getOwnDraft(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) handlerUpdateDraft(w http.ResponseWriter, r *http.Request) {
+	draft, ok := cfg.getOwnDraft(w, r)
+	if !ok {
+		return
+	}
+
+	params, ok := decodeDraftParameters(w, r)
+	if !ok {
+		return
+	}
+
+	pollOptions, pollClosesAt := draftPollColumns(params.Poll)
+	updated, err := cfg.db.UpdateDraft(r.Context(), database.UpdateDraftParams{
+		Body:         params.Body,
+		InReplyTo:    nullUUIDFromPtr(params.InReplyTo),
+		QuoteOf:      nullUUIDFromPtr(params.QuoteOf),
+		MediaIds:     params.MediaIDs,
+		Visibility:   params.Visibility,
+		PublishAt:    nullTimeFromPtr(params.PublishAt),
+		PollOptions:  pollOptions,
+		PollClosesAt: pollClosesAt,
+		ID:           draft.ID,
+		UserID:       draft.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Draft not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update draft", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, draftFromDB(updated))
+}
+
+func (cfg *apiConfig) handlerDeleteDraft(w http.ResponseWriter, r *http.Request) {
+	draft, ok := cfg.getOwnDraft(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := cfg.db.DeleteDraft(r.Context(), database.DeleteDraftParams{
+		ID:     draft.ID,
+		UserID: draft.UserID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete draft", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerPublishDraft(w http.ResponseWriter, r *http.Request) {
+	draft, ok := cfg.getOwnDraft(w, r)
+	if !ok {
+		return
+	}
+
+	// The chirps a draft replies to or quotes may have gone since it was
+	// saved. Publishing it anyway would drop the reply or quote unnoticed.
+	for _, linked := range []struct {
+		id      uuid.NullUUID
+		message string
+	}{
+		{draft.InReplyTo, "Chirp being replied to no longer exists"},
+		{draft.QuoteOf, "Chirp being quoted no longer exists"},
+	} {
+		if !linked.id.Valid {
+			continue
+		}
+		_, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+			ID:       linked.id.UUID,
+			ViewerID: uuid.NullUUID{UUID: draft.UserID, Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusConflict, linked.message, err)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+			return
+		}
+	}
+
+	prepared, ok := cfg.prepareChirp(w, r, draft.UserID, chirpInput{
+		Body:       draft.Body,
+		InReplyTo:  ptrFromNullUUID(draft.InReplyTo),
+		QuoteOf:    ptrFromNullUUID(draft.QuoteOf),
+		MediaIDs:   draft.MediaIds,
+		PublishAt:  ptrFromNullTime(draft.PublishAt),
+		Poll:       draftPoll(draft),
+		Visibility: draft.Visibility,
+	})
+	if !ok {
+		return
+	}
+
+	chirp, err := cfg.publishDraft(r.Context(), draft, prepared)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Draft not found", err)
+			return
+		}
+		if errors.Is(err, errInvalidMedia) {
+			respondWithError(w, http.StatusBadRequest, "Invalid media", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Error creating chirp in database", err)
+		return
+	}
+
+	cfg.respondWithCreatedChirp(w, r, chirp)
+}
+
+// publishDraft creates the chirp and deletes the draft in one transaction,
+// so a draft is never published twice or lost. It returns sql.ErrNoRows if
+// the draft was already published or deleted.
+func (cfg *apiConfig) publishDraft(ctx context.Context, draft database.Draft, prepared preparedChirp) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	deleted, err := qtx.DeleteDraft(ctx, database.DeleteDraftParams{
+		ID:     draft.ID,
+		UserID: draft.UserID,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	if deleted == 0 {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+
+	chirp, err := storeChirp(ctx, qtx, prepared)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	return chirp, tx.Commit()
+}
+
+// getOwnDraft loads the caller's draft named in the path, responding with an
+// error if it doesn't exist. Other users' drafts are reported as not found.
+func (cfg *apiConfig) getOwnDraft(w http.ResponseWriter, r *http.Request) (database.Draft, bool) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return database.Draft{}, false
+	}
+
+	draftID, err := uuid.Parse(r.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid draft ID", err)
+		return database.Draft{}, false
+	}
+
+	draft, err := cfg.db.GetDraft(r.Context(), database.GetDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Draft not found", err)
+			return database.Draft{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get draft", err)
+		return database.Draft{}, false
+	}
+
+	return draft, true
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestPublishDraftKeepsChirpOptions(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, strangerToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	closesAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	rec := serveTestRequest(t, "POST /api/drafts", cfg.middlewareAuth(cfg.handlerCreateDraft), "/api/drafts", authorToken, map[string]any{
+		"body":       "lunch?",
+		"visibility": visibilityFollowers,
+		"poll": map[string]any{
+			"options":   []string{"tacos", "ramen"},
+			"closes_at": closesAt,
+		},
+	})
+	var draft Draft
+	expectStatus(t, rec, http.StatusCreated, &draft)
+
+	rec = serveTestRequest(t, "GET /api/drafts/{draftID}", cfg.middlewareAuth(cfg.handlerGetDraft),
+		"/api/drafts/"+draft.ID.String(), authorToken, nil)
+	expectStatus(t, rec, http.StatusOK, &draft)
+	if draft.Visibility != visibilityFollowers {
+		t.Errorf("draft visibility = %q, want %q", draft.Visibility, visibilityFollowers)
+	}
+	if draft.Poll == nil || len(draft.Poll.Options) != 2 || !draft.Poll.ClosesAt.Equal(closesAt) {
+		t.Fatalf("draft poll = %+v, want two options closing at %v", draft.Poll, closesAt)
+	}
+
+	rec = serveTestRequest(t, "POST /api/drafts/{draftID}/publish", cfg.middlewareAuth(cfg.handlerPublishDraft),
+		"/api/drafts/"+draft.ID.String()+"/publish", authorToken, nil)
+	var chirp Chirp
+	expectStatus(t, rec, http.StatusCreated, &chirp)
+	if chirp.Visibility != visibilityFollowers {
+		t.Errorf("chirp visibility = %q, want %q", chirp.Visibility, visibilityFollowers)
+	}
+	if chirp.Poll == nil || len(chirp.Poll.Options) != 2 {
+		t.Errorf("chirp poll = %+v, want two options", chirp.Poll)
+	}
+
+	rec = serveTestRequest(t, "GET /api/chirps/{chirpID}", cfg.middlewareOptionalAuth(cfg.handlerGetChirp),
+		"/api/chirps/"+chirp.ID.String(), strangerToken, nil)
+	expectStatus(t, rec, http.StatusNotFound, nil)
+}
+
+func TestPublishScheduledDraft(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	rec := serveTestRequest(t, "POST /api/drafts", cfg.middlewareAuth(cfg.handlerCreateDraft), "/api/drafts", authorToken, map[string]any{
+		"body":       "see you soon",
+		"publish_at": publishAt,
+	})
+	var draft Draft
+	expectStatus(t, rec, http.StatusCreated, &draft)
+
+	rec = serveTestRequest(t, "POST /api/drafts/{draftID}/publish", cfg.middlewareAuth(cfg.handlerPublishDraft),
+		"/api/drafts/"+draft.ID.String()+"/publish", authorToken, nil)
+	var chirp Chirp
+	expectStatus(t, rec, http.StatusCreated, &chirp)
+	if chirp.PublishAt == nil || !chirp.PublishAt.Equal(publishAt) {
+		t.Errorf("chirp publish_at = %v, want %v", chirp.PublishAt, publishAt)
+	}
+
+	rec = serveTestRequest(t, "GET /api/chirps/{chirpID}", cfg.middlewareOptionalAuth(cfg.handlerGetChirp),
+		"/api/chirps/"+chirp.ID.String(), "", nil)
+	expectStatus(t, rec, http.StatusNotFound, nil)
+}
+
+func TestPublishDraftWithDeletedChirp(t *testing.T) {
+	testCases := []struct {
+		name  string
+		field string
+	}{
+		{name: "reply", field: "in_reply_to"},
+		{name: "quote", field: "quote_of"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+
+			_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+			_, otherToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+			linked := createTestChirp(t, cfg, otherToken, map[string]any{"body": "hot take"})
+
+			rec := serveTestRequest(t, "POST /api/drafts", cfg.middlewareAuth(cfg.handlerCreateDraft), "/api/drafts", authorToken, map[string]any{
+				"body":   "I disagree",
+				tc.field: linked.ID,
+			})
+			var draft Draft
+			expectStatus(t, rec, http.StatusCreated, &draft)
+
+			rec = serveTestRequest(t, "DELETE /api/chirps/{chirpID}", cfg.middlewareAuth(cfg.handlerDeleteChirp),
+				"/api/chirps/"+linked.ID.String(), otherToken, nil)
+			expectStatus(t, rec, http.StatusNoContent, nil)
+
+			rec = serveTestRequest(t, "POST /api/drafts/{draftID}/publish", cfg.middlewareAuth(cfg.handlerPublishDraft),
+				"/api/drafts/"+draft.ID.String()+"/publish", authorToken, nil)
+			expectStatus(t, rec, http.StatusConflict, nil)
+
+			// The draft is kept so the author can decide what to do with it.
+			rec = serveTestRequest(t, "GET /api/drafts/{draftID}", cfg.middlewareAuth(cfg.handlerGetDraft),
+				"/api/drafts/"+draft.ID.String(), authorToken, nil)
+			expectStatus(t, rec, http.StatusOK, &draft)
+			if draft.InReplyTo.UUID != linked.ID && draft.QuoteOf.UUID != linked.ID {
+				t.Errorf("draft lost its link to %v: %+v", linked.ID, draft)
+			}
+		})
+	}
+}
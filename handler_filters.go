@@ -0,0 +1,159 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/filters"
+	"github.com/google/uuid"
+)
+
+// MuteFilter hides chirps matching a word, phrase or hashtag from its owner's
+// chirp lists, search results and timeline until it expires.
+type MuteFilter struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Kind      string     `json:"kind"`
+	Value     string     `json:"value"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func muteFilterFromDB(filter database.MuteFilter) MuteFilter {
+	f := MuteFilter{
+		ID:        filter.ID,
+		CreatedAt: filter.CreatedAt,
+		Kind:      filter.Kind,
+		Value:     filter.Value,
+	}
+	if filter.ExpiresAt.Valid {
+		f.ExpiresAt = &filter.ExpiresAt.Time
+	}
+	return f
+}
+
+// handlerCreateMuteFilter adds a filter for the caller. Adding a filter they
+// already have replaces its expiry.
+func (cfg *apiConfig) handlerCreateMuteFilter(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	type parameters struct {
+		Kind      string     `json:"kind"`
+		Value     string     `json:"value"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	kind, err := filters.ParseKind(params.Kind)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid kind", err)
+		return
+	}
+
+	value, err := filters.Normalize(kind, params.Value)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid value", err)
+		return
+	}
+
+	// Words like "the" are left out of search vectors, so a filter made only
+	// of them would never match anything.
+	if kind != filters.KindHashtag {
+		empty, err := cfg.db.MuteFilterQueryIsEmpty(r.Context(), value)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to check filter", err)
+			return
+		}
+		if empty {
+			respondWithError(w, http.StatusBadRequest, "Value only has words too common to match", nil)
+			return
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if params.ExpiresAt != nil {
+		if !params.ExpiresAt.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "expires_at must be in the future", nil)
+			return
+		}
+		expiresAt = sql.NullTime{Time: *params.ExpiresAt, Valid: true}
+	}
+
+	filter, err := cfg.db.CreateMuteFilter(r.Context(), database.CreateMuteFilterParams{
+		UserID:    userID,
+		Kind:      string(kind),
+		Value:     value,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create filter", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, muteFilterFromDB(filter))
+}
+
+// handlerGetMuteFilters lists the caller's filters that haven't expired,
+// oldest first.
+func (cfg *apiConfig) handlerGetMuteFilters(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	rows, err := cfg.db.GetMuteFilters(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get filters", err)
+		return
+	}
+
+	muteFilters := []MuteFilter{}
+	for _, row := range rows {
+		muteFilters = append(muteFilters, muteFilterFromDB(row))
+	}
+
+	respondWithJSON(w, http.StatusOK, muteFilters)
+}
+
+func (cfg *apiConfig) handlerDeleteMuteFilter(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	filterID, err := uuid.Parse(r.PathValue("filterID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid filter ID", err)
+		return
+	}
+
+	deleted, err := cfg.db.DeleteMuteFilter(r.Context(), database.DeleteMuteFilterParams{
+		ID:     filterID,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete filter", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Filter not found", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/google/uuid"
+)
+
+func createTestMuteFilter(t *testing.T, cfg *apiConfig, token, kind, value string) {
+	t.Helper()
+
+	rec := serveTestRequest(t, "POST /api/filters", cfg.middlewareAuth(cfg.handlerCreateMuteFilter),
+		"/api/filters", token, map[string]any{"kind": kind, "value": value})
+	expectStatus(t, rec, http.StatusCreated, nil)
+}
+
+func TestCreateMuteFilterRejectsStopwords(t *testing.T) {
+	testCases := []struct {
+		kind  string
+		value string
+		want  int
+	}{
+		{kind: "word", value: "the", want: http.StatusBadRequest},
+		{kind: "phrase", value: "to be or not to be", want: http.StatusBadRequest},
+		{kind: "phrase", value: "the finale", want: http.StatusCreated},
+		{kind: "word", value: "finale", want: http.StatusCreated},
+	}
+
+	cfg := newTestConfig(t)
+	_, token := createTestUser(t, cfg, "", auth.RoleUser)
+
+	for _, tc := range testCases {
+		t.Run(tc.kind+"/"+tc.value, func(t *testing.T) {
+			rec := serveTestRequest(t, "POST /api/filters", cfg.middlewareAuth(cfg.handlerCreateMuteFilter),
+				"/api/filters", token, map[string]any{"kind": tc.kind, "value": tc.value})
+			expectStatus(t, rec, tc.want, nil)
+		})
+	}
+}
+
+func TestMuteFilterHidesRechirps(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, rechirperToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, viewerToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	muted := createTestChirp(t, cfg, authorToken, map[string]any{"body": "huge spoilers ahead"})
+	rechirp := createTestChirp(t, cfg, rechirperToken, map[string]any{"rechirp_of": muted.ID})
+	createTestMuteFilter(t, cfg, viewerToken, "word", "spoilers")
+
+	rec := serveTestRequest(t, "GET /api/chirps", cfg.middlewareOptionalAuth(cfg.handlerGetChirps), "/api/chirps", viewerToken, nil)
+	var page chirpsPage
+	expectStatus(t, rec, http.StatusOK, &page)
+	for _, chirp := range page.Chirps {
+		if chirp.ID == muted.ID || chirp.ID == rechirp.ID {
+			t.Errorf("got muted chirp %v (rechirp of %v)", chirp.ID, chirp.rechirpOfID)
+		}
+	}
+}
+
+func TestMuteFilterHidesPinnedChirps(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	author, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, viewerToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	chirp := createTestChirp(t, cfg, authorToken, map[string]any{"body": "the finale was great #tvshow"})
+	rec := serveTestRequest(t, "POST /api/chirps/{chirpID}/pin", cfg.middlewareAuth(cfg.handlerPinChirp),
+		"/api/chirps/"+chirp.ID.String()+"/pin", authorToken, nil)
+	if rec.Code >= 300 {
+		t.Fatalf("pinning chirp: status %d; body: %s", rec.Code, rec.Body)
+	}
+
+	testCases := []struct {
+		kind  string
+		value string
+	}{
+		{kind: "word", value: "finale"},
+		{kind: "hashtag", value: "#tvshow"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.kind, func(t *testing.T) {
+			_, viewerToken := createTestUser(t, cfg, "", auth.RoleUser)
+			createTestMuteFilter(t, cfg, viewerToken, tc.kind, tc.value)
+
+			profile := getTestProfile(t, cfg, author.ID, viewerToken)
+			if len(profile.PinnedChirps) != 0 {
+				t.Errorf("pinned chirps = %+v, want the muted chirp hidden", profile.PinnedChirps)
+			}
+		})
+	}
+
+	profile := getTestProfile(t, cfg, author.ID, viewerToken)
+	if len(profile.PinnedChirps) != 1 {
+		t.Errorf("got %d pinned chirps without a filter, want 1", len(profile.PinnedChirps))
+	}
+}
+
+func getTestProfile(t *testing.T, cfg *apiConfig, userID uuid.UUID, token string) PublicUser {
+	t.Helper()
+
+	rec := serveTestRequest(t, "GET /api/users/{userID}", cfg.middlewareOptionalAuth(cfg.handlerGetUserProfile),
+		"/api/users/"+userID.String(), token, nil)
+	var profile PublicUser
+	expectStatus(t, rec, http.StatusOK, &profile)
+	return profile
+}
+
+func TestMuteFilterExpiryIgnoresSessionTimeZone(t *testing.T) {
+	cfg := newTestConfigInTimeZone(t, testTimeZone)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, viewerToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	muted := createTestChirp(t, cfg, authorToken, map[string]any{"body": "huge spoilers ahead"})
+	rec := serveTestRequest(t, "POST /api/filters", cfg.middlewareAuth(cfg.handlerCreateMuteFilter), "/api/filters", viewerToken, map[string]any{
+		"kind":       "word",
+		"value":      "spoilers",
+		"expires_at": time.Now().Add(time.Hour),
+	})
+	expectStatus(t, rec, http.StatusCreated, nil)
+
+	rec = serveTestRequest(t, "GET /api/chirps", cfg.middlewareOptionalAuth(cfg.handlerGetChirps), "/api/chirps", viewerToken, nil)
+	var page chirpsPage
+	expectStatus(t, rec, http.StatusOK, &page)
+	for _, chirp := range page.Chirps {
+		if chirp.ID == muted.ID {
+			t.Errorf("got chirp %v muted by a filter that hasn't expired", chirp.ID)
+		}
+	}
+}
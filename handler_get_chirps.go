@@ -1,13 +1,29 @@
 package main
 
 import (
+	"context"
 	"net/http"
-	"sort"
 
 	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
 	"github.com/google/uuid"
 )
 
+type chirpsPage struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+func newChirpsPage(chirps []database.Chirp, limit int32) chirpsPage {
+	chirps, next := pagination.Trim(chirps, limit, chirpKey)
+
+	page := chirpsPage{Chirps: []Chirp{}, NextCursor: next}
+	for _, chirp := range chirps {
+		page.Chirps = append(page.Chirps, chirpFromDB(chirp))
+	}
+	return page
+}
+
 func (cfg *apiConfig) handlerGetChirp(w http.ResponseWriter, r *http.Request) {
 
 	chirpString := r.PathValue("chirpID")
@@ -18,64 +34,110 @@ func (cfg *apiConfig) handlerGetChirp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chirp, err := cfg.db.GetChirp(r.Context(), chirpID)
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
 
 	if err != nil {
 		respondWithError(w, http.StatusNotFound, "Unable to find chirp", err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, Chirp{
-		ID:        chirp.ID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
-	})
+	chirps := []Chirp{chirpFromDB(chirp)}
+	err = cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
 
 }
 
 func (cfg *apiConfig) handlerGetChirps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
 
-	var chirps []database.Chirp
-	var err error
-
-	sortOrder := r.URL.Query().Get("sort")
+	page, err := pagination.ParseParams(query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
 
-	authorIDStr := r.URL.Query().Get("author_id")
-	if authorIDStr != "" {
+	var authorID uuid.NullUUID
+	if authorIDStr := query.Get("author_id"); authorIDStr != "" {
 		uID, err := uuid.Parse(authorIDStr)
 		if err != nil {
 			respondWithError(w, http.StatusBadRequest, "invalid user id", err)
+			return
 		}
-		chirps, err = cfg.db.GetChirpsByUser(r.Context(), uID)
-		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Failed to get chirps from db", err)
-		}
+		authorID = uuid.NullUUID{UUID: uID, Valid: true}
+	}
+
+	// Fetch one extra row so we know whether there is another page.
+	var chirps []database.Chirp
+	if query.Get("sort") == "desc" {
+		chirps, err = cfg.db.GetChirpsDesc(r.Context(), database.GetChirpsDescParams{
+			AuthorID:        authorID,
+			CursorCreatedAt: page.CursorCreatedAt,
+			CursorID:        page.CursorID,
+			ViewerID:        getViewerID(r.Context()),
+			Limit:           page.Limit + 1,
+		})
 	} else {
-		chirps, err = cfg.db.GetChirps(r.Context())
+		chirps, err = cfg.db.GetChirpsAsc(r.Context(), database.GetChirpsAscParams{
+			AuthorID:        authorID,
+			CursorCreatedAt: page.CursorCreatedAt,
+			CursorID:        page.CursorID,
+			ViewerID:        getViewerID(r.Context()),
+			Limit:           page.Limit + 1,
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get chirps from db", err)
+		return
+	}
+
+	chirpsResp := newChirpsPage(chirps, page.Limit)
+	if authorID.Valid {
+		chirpsResp.Chirps, err = cfg.pinChirpsFirst(r.Context(), authorID.UUID, chirpsResp.Chirps, page.CursorID.Valid)
 		if err != nil {
-			respondWithError(w, http.StatusInternalServerError, "Failed to get chirps from db", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to get pinned chirps", err)
+			return
 		}
 	}
 
-	var chirpsArr []Chirp
+	err = cfg.hydrateChirps(r.Context(), chirpsResp.Chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
 
-	for _, chirp := range chirps {
-		chirpsArr = append(chirpsArr, Chirp{
-			ID:        chirp.ID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-			Body:      chirp.Body,
-			UserID:    chirp.UserID,
-		})
+	respondWithJSON(w, http.StatusOK, chirpsResp)
+}
+
+// pinChirpsFirst puts the author's pinned chirps at the top of the first page
+// of their chirps and drops them from where they'd otherwise appear, so a
+// pinned chirp is only listed once.
+func (cfg *apiConfig) pinChirpsFirst(ctx context.Context, authorID uuid.UUID, chirps []Chirp, hasCursor bool) ([]Chirp, error) {
+	pinned, err := cfg.getPinnedChirps(ctx, authorID)
+	if err != nil {
+		return nil, err
 	}
 
-	if sortOrder == "desc" {
-		sort.Slice(chirpsArr, func(i, j int) bool {
-			return chirpsArr[i].CreatedAt.After(chirpsArr[j].CreatedAt)
-		})
+	isPinned := map[uuid.UUID]bool{}
+	for _, chirp := range pinned {
+		isPinned[chirp.ID] = true
 	}
 
-	respondWithJSON(w, http.StatusOK, chirpsArr)
+	result := []Chirp{}
+	if !hasCursor {
+		result = append(result, pinned...)
+	}
+	for _, chirp := range chirps {
+		if !isPinned[chirp.ID] {
+			result = append(result, chirp)
+		}
+	}
+	return result, nil
 }
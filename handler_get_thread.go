@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+type ThreadChirp struct {
+	Chirp
+	Depth   int32          `json:"depth"`
+	Deleted bool           `json:"deleted"`
+	Replies []*ThreadChirp `json:"replies"`
+}
+
+func (cfg *apiConfig) handlerGetThread(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Unable to parse given chirpID", err)
+		return
+	}
+
+	rows, err := cfg.db.GetThread(r.Context(), database.GetThreadParams{
+		ChirpID:  chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get thread", err)
+		return
+	}
+	if len(rows) == 0 {
+		respondWithError(w, http.StatusNotFound, "Unable to find chirp", nil)
+		return
+	}
+
+	chirps := []Chirp{}
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row.Chirp))
+	}
+	err = cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	// Rows come back ordered by depth, so every parent is seen before its
+	// replies. Chirps the caller can't see are missing, which prunes their
+	// replies from the tree too.
+	nodes := make(map[uuid.UUID]*ThreadChirp, len(rows))
+
+	for i, row := range rows {
+		node := &ThreadChirp{
+			Chirp:   chirps[i],
+			Depth:   row.Depth,
+			Deleted: row.Chirp.DeletedAt.Valid,
+			Replies: []*ThreadChirp{},
+		}
+		nodes[row.Chirp.ID] = node
+
+		if parent, ok := nodes[row.Chirp.InReplyTo.UUID]; ok && row.Chirp.InReplyTo.Valid {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	root, ok := nodes[chirpID]
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unable to find chirp", nil)
+		return
+	}
+	// Climb to the highest ancestor the caller can see.
+	for root.InReplyTo.Valid {
+		parent, ok := nodes[root.InReplyTo.UUID]
+		if !ok {
+			break
+		}
+		root = parent
+	}
+
+	respondWithJSON(w, http.StatusOK, root)
+}
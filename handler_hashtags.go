@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/hashtags"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+)
+
+const (
+	defaultTrendingWindow = 24 * time.Hour
+	maxTrendingWindow     = 7 * 24 * time.Hour
+	trendingLimit         = 10
+)
+
+type TrendingHashtag struct {
+	Tag        string `json:"tag"`
+	ChirpCount int64  `json:"chirp_count"`
+}
+
+func (cfg *apiConfig) handlerGetHashtagChirps(w http.ResponseWriter, r *http.Request) {
+	tag, ok := hashtags.Normalize(r.PathValue("tag"))
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid hashtag", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	chirps, err := cfg.db.GetChirpsByHashtag(r.Context(), database.GetChirpsByHashtagParams{
+		Tag:             tag,
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		ViewerID:        getViewerID(r.Context()),
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get chirps from db", err)
+		return
+	}
+
+	tagged := newChirpsPage(chirps, page.Limit)
+	err = cfg.hydrateChirps(r.Context(), tagged.Chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tagged)
+}
+
+func (cfg *apiConfig) handlerGetTrendingHashtags(w http.ResponseWriter, r *http.Request) {
+	window := defaultTrendingWindow
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		parsed, err := time.ParseDuration(windowStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid window", err)
+			return
+		}
+		if parsed <= 0 || parsed > maxTrendingWindow {
+			respondWithError(w, http.StatusBadRequest, "invalid window", errors.New("window must be between 0 and 168h"))
+			return
+		}
+		window = parsed
+	}
+
+	rows, err := cfg.db.GetTrendingHashtags(r.Context(), database.GetTrendingHashtagsParams{
+		Since: time.Now().Add(-window),
+		Limit: trendingLimit,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get trending hashtags", err)
+		return
+	}
+
+	trending := []TrendingHashtag{}
+	for _, row := range rows {
+		trending = append(trending, TrendingHashtag{
+			Tag:        row.Tag,
+			ChirpCount: row.ChirpCount,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, trending)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestEditingOldChirpDoesNotTrend(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	author, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	// Chirpy Red members can edit a chirp however old it is.
+	err := cfg.db.UpgradeUser(t.Context(), author.ID)
+	if err != nil {
+		t.Fatalf("upgrading user: %v", err)
+	}
+
+	chirp := createTestChirp(t, cfg, authorToken, map[string]any{"body": "learning #golang"})
+
+	_, err = cfg.dbConn.ExecContext(t.Context(), "UPDATE chirps SET created_at = created_at - interval '3 days' WHERE id = $1", chirp.ID)
+	if err != nil {
+		t.Fatalf("backdating chirp: %v", err)
+	}
+	_, err = cfg.dbConn.ExecContext(t.Context(), "UPDATE chirp_hashtags SET created_at = created_at - interval '3 days' WHERE chirp_id = $1", chirp.ID)
+	if err != nil {
+		t.Fatalf("backdating hashtags: %v", err)
+	}
+
+	rec := serveTestRequest(t, "PUT /api/chirps/{chirpID}", cfg.middlewareAuth(cfg.handlerUpdateChirp),
+		"/api/chirps/"+chirp.ID.String(), authorToken, map[string]any{"body": "learning #golang and #rust"})
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	rec = serveTestRequest(t, "GET /api/hashtags/trending", cfg.handlerGetTrendingHashtags, "/api/hashtags/trending", "", nil)
+	var trending []TrendingHashtag
+	expectStatus(t, rec, http.StatusOK, &trending)
+	if len(trending) != 0 {
+		t.Errorf("trending = %+v, want nothing from a chirp posted 3 days ago", trending)
+	}
+}
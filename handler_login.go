@@ -55,7 +55,17 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, err := auth.MakeJWT(user.ID, cfg.jwtSecret, time.Hour)
+	suspended, err := cfg.isSuspended(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check account status", err)
+		return
+	}
+	if suspended {
+		respondWithError(w, http.StatusForbidden, "Account suspended", nil)
+		return
+	}
+
+	accessToken, err := auth.MakeJWT(user.ID, auth.Role(user.Role), cfg.jwtSecret, time.Hour)
 
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't create access JWT", err)
@@ -82,6 +92,11 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, r *http.Request) {
 			UpdatedAt:   user.UpdatedAt,
 			Email:       user.Email,
 			IsChirpyRed: user.IsChirpyRed,
+			Handle:      user.Handle.String,
+			DisplayName: user.DisplayName,
+			Bio:         user.Bio,
+			AvatarURL:   user.AvatarUrl,
+			Role:        user.Role,
 		},
 
 		Token:        accessToken,
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/media"
+	"github.com/google/uuid"
+)
+
+const maxMediaPerChirp = 4
+
+var errInvalidMedia = errors.New("media must be your own unattached uploads")
+
+type Media struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+func mediaFromDB(m database.Medium) Media {
+	return Media{
+		ID:           m.ID,
+		URL:          "/media/" + m.BlobKey,
+		ThumbnailURL: "/media/" + m.ThumbnailKey,
+		ContentType:  m.ContentType,
+		Width:        m.Width,
+		Height:       m.Height,
+	}
+}
+
+func (cfg *apiConfig) handlerUploadMedia(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	// Leave a little room for the multipart framing around the file itself.
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxUploadSize+1<<10)
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "File is too large", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Couldn't read file from form", err)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, media.MaxUploadSize+1))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't read file", err)
+		return
+	}
+	if len(data) > media.MaxUploadSize {
+		respondWithError(w, http.StatusRequestEntityTooLarge, "File is too large", nil)
+		return
+	}
+
+	img, err := media.ProcessImage(data)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid image", err)
+		return
+	}
+
+	mediaID := uuid.New()
+	blobKey := mediaID.String() + img.Extension
+	thumbnailKey := mediaID.String() + "_thumb" + img.Extension
+
+	err = cfg.blobStore.Put(r.Context(), blobKey, bytes.NewReader(img.Data))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't store image", err)
+		return
+	}
+	err = cfg.blobStore.Put(r.Context(), thumbnailKey, bytes.NewReader(img.Thumbnail))
+	if err != nil {
+		cfg.deleteBlobs(r.Context(), blobKey)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't store thumbnail", err)
+		return
+	}
+
+	m, err := cfg.db.CreateMedia(r.Context(), database.CreateMediaParams{
+		ID:           mediaID,
+		UserID:       userID,
+		ContentType:  img.ContentType,
+		BlobKey:      blobKey,
+		ThumbnailKey: thumbnailKey,
+		Width:        int32(img.Width),
+		Height:       int32(img.Height),
+		SizeBytes:    int64(len(img.Data)),
+	})
+	if err != nil {
+		cfg.deleteBlobs(r.Context(), blobKey, thumbnailKey)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save media", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, mediaFromDB(m))
+}
+
+// handlerServeMedia serves an image or thumbnail to anyone who can read the
+// chirp it's attached to. Uploads that aren't attached yet are only served
+// to the user who uploaded them.
+func (cfg *apiConfig) handlerServeMedia(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+
+	// Media the caller can't see is reported missing so keys can't be probed.
+	isPublic, err := cfg.db.GetViewableMedia(r.Context(), database.GetViewableMediaParams{
+		Key:      key,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Media not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get media", err)
+		return
+	}
+
+	blob, err := cfg.blobStore.Open(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, media.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Media not found", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Couldn't open media", err)
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", mime.TypeByExtension(filepath.Ext(key)))
+	if isPublic {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, blob)
+}
+
+// deleteBlobs removes blobs whose rows are already gone. Failures only leave
+// orphaned files behind, so they are logged rather than surfaced.
+func (cfg *apiConfig) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		err := cfg.blobStore.Delete(ctx, key)
+		if err != nil {
+			log.Printf("Error deleting blob %s: %s", key, err)
+		}
+	}
+}
+
+func (cfg *apiConfig) setMedia(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	chirpIDs := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		chirpIDs = append(chirpIDs, chirp.ID)
+	}
+
+	rows, err := cfg.db.GetMediaForChirps(ctx, chirpIDs)
+	if err != nil {
+		return err
+	}
+
+	byChirp := map[uuid.UUID][]Media{}
+	for _, row := range rows {
+		byChirp[row.ChirpID.UUID] = append(byChirp[row.ChirpID.UUID], mediaFromDB(row))
+	}
+
+	for i := range chirps {
+		if attached, ok := byChirp[chirps[i].ID]; ok {
+			chirps[i].Media = attached
+		}
+	}
+	return nil
+}
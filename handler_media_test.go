@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+// uploadTestImage uploads a small PNG as the user token belongs to.
+func uploadTestImage(t *testing.T, cfg *apiConfig, token string) Media {
+	t.Helper()
+
+	var img bytes.Buffer
+	err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	if err != nil {
+		t.Fatalf("encoding image: %v", err)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "test.png")
+	if err != nil {
+		t.Fatalf("creating form: %v", err)
+	}
+	part.Write(img.Bytes())
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/media", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	cfg.middlewareAuth(cfg.handlerUploadMedia)(rec, req)
+
+	var m Media
+	expectStatus(t, rec, http.StatusCreated, &m)
+	return m
+}
+
+func TestServeMediaChecksChirpVisibility(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	author, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, followerToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, strangerToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	rec := serveTestRequest(t, "POST /api/users/{userID}/follow", cfg.middlewareAuth(cfg.handlerFollowUser),
+		"/api/users/"+author.ID.String()+"/follow", followerToken, nil)
+	if rec.Code >= 300 {
+		t.Fatalf("following author: status %d; body: %s", rec.Code, rec.Body)
+	}
+
+	m := uploadTestImage(t, cfg, authorToken)
+	serveMedia := cfg.middlewareOptionalAuth(cfg.handlerServeMedia)
+
+	// Until it's attached, only the uploader can see it.
+	expectStatus(t, serveTestRequest(t, "GET /media/{key}", serveMedia, m.URL, authorToken, nil), http.StatusOK, nil)
+	expectStatus(t, serveTestRequest(t, "GET /media/{key}", serveMedia, m.URL, followerToken, nil), http.StatusNotFound, nil)
+
+	createTestChirp(t, cfg, authorToken, map[string]any{
+		"body":       "for my followers",
+		"visibility": visibilityFollowers,
+		"media_ids":  []any{m.ID},
+	})
+
+	testCases := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "author", token: authorToken, want: http.StatusOK},
+		{name: "follower", token: followerToken, want: http.StatusOK},
+		{name: "stranger", token: strangerToken, want: http.StatusNotFound},
+		{name: "anonymous", want: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, url := range []string{m.URL, m.ThumbnailURL} {
+				rec := serveTestRequest(t, "GET /media/{key}", serveMedia, url, tc.token, nil)
+				expectStatus(t, rec, tc.want, nil)
+				if tc.want == http.StatusOK && rec.Header().Get("Cache-Control") != "private, no-store" {
+					t.Errorf("Cache-Control = %q for followers-only media", rec.Header().Get("Cache-Control"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/moderation"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+type ModerationRule struct {
+	Word      string    `json:"word"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ModerationFlag struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Body      string    `json:"body"`
+	Words     []string  `json:"words"`
+}
+
+type moderationFlagsPage struct {
+	Flags      []ModerationFlag `json:"flags"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+func moderationRuleFromDB(rule database.ModerationRule) ModerationRule {
+	return ModerationRule{
+		Word:      rule.Word,
+		Action:    rule.Action,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+}
+
+// handlerGetModerationRules lists the rules managed through the admin API.
+// Rules from the word list file aren't included.
+func (cfg *apiConfig) handlerGetModerationRules(w http.ResponseWriter, r *http.Request) {
+	rows, err := cfg.db.GetModerationRules(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get moderation rules", err)
+		return
+	}
+
+	rules := []ModerationRule{}
+	for _, row := range rows {
+		rules = append(rules, moderationRuleFromDB(row))
+	}
+
+	respondWithJSON(w, http.StatusOK, rules)
+}
+
+// handlerPutModerationRule adds a word to the list or changes its action.
+// The new list applies to chirps posted from the moment it responds.
+func (cfg *apiConfig) handlerPutModerationRule(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Action string `json:"action"`
+	}
+
+	word, err := moderation.NormalizeWord(r.PathValue("word"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid word", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	action, err := moderation.ParseAction(params.Action)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid action", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save moderation rule", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	var before *ModerationRule
+	existing, err := qtx.GetModerationRule(r.Context(), word)
+	if err == nil {
+		rule := moderationRuleFromDB(existing)
+		before = &rule
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get moderation rule", err)
+		return
+	}
+
+	rule, err := qtx.UpsertModerationRule(r.Context(), database.UpsertModerationRuleParams{
+		Word:   word,
+		Action: string(action),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save moderation rule", err)
+		return
+	}
+
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionPutRule,
+		TargetType: auditTargetModerationRule,
+		TargetID:   word,
+		Before:     before,
+		After:      moderationRuleFromDB(rule),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record moderation rule change", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save moderation rule", err)
+		return
+	}
+
+	err = cfg.reloadWordList(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to reload moderation word list", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, moderationRuleFromDB(rule))
+}
+
+func (cfg *apiConfig) handlerDeleteModerationRule(w http.ResponseWriter, r *http.Request) {
+	word, err := moderation.NormalizeWord(r.PathValue("word"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid word", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete moderation rule", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	rule, err := qtx.GetModerationRule(r.Context(), word)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Moderation rule not found", nil)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get moderation rule", err)
+		return
+	}
+
+	_, err = qtx.DeleteModerationRule(r.Context(), word)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete moderation rule", err)
+		return
+	}
+
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionDeleteRule,
+		TargetType: auditTargetModerationRule,
+		TargetID:   word,
+		Before:     moderationRuleFromDB(rule),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record moderation rule change", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete moderation rule", err)
+		return
+	}
+
+	err = cfg.reloadWordList(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to reload moderation word list", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerGetModerationFlags lists chirps that used a flagged word, newest
+// first. A chirp is flagged again each time it's edited to use one.
+func (cfg *apiConfig) handlerGetModerationFlags(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.ParseParams(r.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	rows, err := cfg.db.GetModerationFlags(r.Context(), database.GetModerationFlagsParams{
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get moderation flags", err)
+		return
+	}
+
+	rows, next := pagination.Trim(rows, page.Limit, func(row database.GetModerationFlagsRow) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: row.CreatedAt, ID: row.ID}
+	})
+
+	flags := moderationFlagsPage{Flags: []ModerationFlag{}, NextCursor: next}
+	for _, row := range rows {
+		flags.Flags = append(flags.Flags, ModerationFlag{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			ChirpID:   row.ChirpID,
+			UserID:    row.UserID,
+			Body:      row.Body,
+			Words:     row.Words,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, flags)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestMentionNotificationsRespectVisibility(t *testing.T) {
+	testCases := []struct {
+		name         string
+		visibility   string
+		wantFollower int
+		wantStranger int
+	}{
+		{name: "public", visibility: visibilityPublic, wantFollower: 1, wantStranger: 1},
+		{name: "followers", visibility: visibilityFollowers, wantFollower: 1, wantStranger: 0},
+		{name: "mentioned", visibility: visibilityMentioned, wantFollower: 1, wantStranger: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+
+			author, authorToken := createTestUser(t, cfg, "author", auth.RoleUser)
+			_, followerToken := createTestUser(t, cfg, "follower", auth.RoleUser)
+			_, strangerToken := createTestUser(t, cfg, "stranger", auth.RoleUser)
+
+			rec := serveTestRequest(t, "POST /api/users/{userID}/follow", cfg.middlewareAuth(cfg.handlerFollowUser),
+				"/api/users/"+author.ID.String()+"/follow", followerToken, nil)
+			if rec.Code >= 300 {
+				t.Fatalf("following author: status %d; body: %s", rec.Code, rec.Body)
+			}
+
+			createTestChirp(t, cfg, authorToken, map[string]any{
+				"body":       "hey @follower and @stranger",
+				"visibility": tc.visibility,
+			})
+
+			for _, recipient := range []struct {
+				token string
+				want  int
+			}{
+				{followerToken, tc.wantFollower},
+				{strangerToken, tc.wantStranger},
+			} {
+				rec := serveTestRequest(t, "GET /api/notifications", cfg.middlewareAuth(cfg.handlerGetNotifications),
+					"/api/notifications", recipient.token, nil)
+				var page notificationsPage
+				expectStatus(t, rec, http.StatusOK, &page)
+				if len(page.Notifications) != recipient.want {
+					t.Errorf("got %d notifications, want %d", len(page.Notifications), recipient.want)
+				}
+			}
+		})
+	}
+}
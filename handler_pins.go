@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// Everyone gets one pinned chirp; Chirpy Red members get a few more.
+const (
+	maxPinnedChirps    = 1
+	maxPinnedChirpsRed = 3
+)
+
+func (cfg *apiConfig) handlerPinChirp(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		return
+	}
+
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	if userID != chirp.UserID {
+		respondWithError(w, http.StatusForbidden, "You don't own that chirp", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	maxPins := maxPinnedChirps
+	if user.IsChirpyRed {
+		maxPins = maxPinnedChirpsRed
+	}
+
+	pinned, err := cfg.pinChirp(r.Context(), database.PinChirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+		MaxPins: int32(maxPins),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to pin chirp", err)
+		return
+	}
+	// Nothing is inserted if the chirp is already pinned or the user is at
+	// their limit; only the second is an error.
+	if pinned == 0 {
+		pinnedIDs, err := cfg.getPinnedChirpIDs(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to get pinned chirps", err)
+			return
+		}
+		if !pinnedIDs[chirp.ID] {
+			respondWithError(w, http.StatusConflict, "You've already pinned as many chirps as you can", nil)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pinChirp pins a chirp if the user has room for another pin. The user's row
+// is locked while their pins are counted, so two pins at once can't both
+// squeeze under the limit.
+func (cfg *apiConfig) pinChirp(ctx context.Context, params database.PinChirpParams) (int64, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.LockUser(ctx, params.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	pinned, err := qtx.PinChirp(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return pinned, tx.Commit()
+}
+
+func (cfg *apiConfig) handlerUnpinChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	err = cfg.db.UnpinChirp(r.Context(), database.UnpinChirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unpin chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getPinnedChirps returns the author's pinned chirps that the caller can see,
+// most recently pinned first.
+func (cfg *apiConfig) getPinnedChirps(ctx context.Context, authorID uuid.UUID) ([]Chirp, error) {
+	rows, err := cfg.db.GetPinnedChirps(ctx, database.GetPinnedChirpsParams{
+		UserID:   authorID,
+		ViewerID: getViewerID(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := []Chirp{}
+	for _, row := range rows {
+		chirp := chirpFromDB(row)
+		chirp.Pinned = true
+		pinned = append(pinned, chirp)
+	}
+	return pinned, nil
+}
+
+func (cfg *apiConfig) getPinnedChirpIDs(ctx context.Context, authorID uuid.UUID) (map[uuid.UUID]bool, error) {
+	pinned, err := cfg.getPinnedChirps(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[uuid.UUID]bool{}
+	for _, chirp := range pinned {
+		ids[chirp.ID] = true
+	}
+	return ids, nil
+}
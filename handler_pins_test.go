@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestConcurrentPinsRespectLimit(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	author, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	const attempts = 5
+	chirps := make([]Chirp, attempts)
+	for i := range chirps {
+		chirps[i] = createTestChirp(t, cfg, authorToken, map[string]any{"body": "pin me"})
+	}
+
+	pinChirp := cfg.middlewareAuth(cfg.handlerPinChirp)
+	codes := make([]int, attempts)
+	var wg sync.WaitGroup
+	for i, chirp := range chirps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := serveTestRequest(t, "POST /api/chirps/{chirpID}/pin", pinChirp,
+				"/api/chirps/"+chirp.ID.String()+"/pin", authorToken, nil)
+			codes[i] = rec.Code
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusNoContent:
+			succeeded++
+		case http.StatusConflict:
+		default:
+			t.Errorf("pin returned %d", code)
+		}
+	}
+	if succeeded != maxPinnedChirps {
+		t.Errorf("%d pins succeeded, want %d", succeeded, maxPinnedChirps)
+	}
+
+	profile := getTestProfile(t, cfg, author.ID, "")
+	if len(profile.PinnedChirps) != maxPinnedChirps {
+		t.Errorf("profile has %d pinned chirps, want %d", len(profile.PinnedChirps), maxPinnedChirps)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/polls"
+	"github.com/google/uuid"
+)
+
+// Poll is a chirp's poll as seen by the caller. Vote counts are left out
+// until the caller has voted or the poll has closed.
+type Poll struct {
+	ClosesAt      time.Time     `json:"closes_at"`
+	Closed        bool          `json:"closed"`
+	Options       []PollOption  `json:"options"`
+	VotedOptionID uuid.NullUUID `json:"voted_option_id"`
+	TotalVotes    *int32        `json:"total_votes,omitempty"`
+}
+
+type PollOption struct {
+	ID    uuid.UUID `json:"id"`
+	Label string    `json:"label"`
+	Votes *int32    `json:"votes,omitempty"`
+}
+
+type pollInput struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type preparedPoll struct {
+	options  []string
+	closesAt time.Time
+}
+
+// preparePoll validates a poll for a chirp that goes live at opensAt.
+func preparePoll(input pollInput, opensAt time.Time) (*preparedPoll, error) {
+	options, err := polls.NormalizeOptions(input.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = polls.ValidateDuration(opensAt, input.ClosesAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preparedPoll{
+		options:  options,
+		closesAt: input.ClosesAt,
+	}, nil
+}
+
+func storePoll(ctx context.Context, q *database.Queries, chirpID uuid.UUID, poll preparedPoll) error {
+	err := q.CreatePoll(ctx, database.CreatePollParams{
+		ChirpID:  chirpID,
+		ClosesAt: poll.closesAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.CreatePollOptions(ctx, database.CreatePollOptionsParams{
+		ChirpID: chirpID,
+		Labels:  poll.options,
+	})
+}
+
+func (cfg *apiConfig) handlerVotePoll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	type parameters struct {
+		OptionID uuid.UUID `json:"option_id"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		return
+	}
+
+	poll, err := cfg.db.GetPoll(r.Context(), chirp.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Chirp doesn't have a poll", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get poll", err)
+		return
+	}
+
+	if !poll.ClosesAt.After(time.Now()) {
+		respondWithError(w, http.StatusForbidden, "Poll has closed", nil)
+		return
+	}
+
+	voted, err := cfg.db.CreatePollVote(r.Context(), database.CreatePollVoteParams{
+		UserID:   userID,
+		OptionID: params.OptionID,
+		ChirpID:  chirp.ID,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "You've already voted in this poll", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to record vote", err)
+		return
+	}
+	// Nothing is inserted if the option belongs to another poll, or if the
+	// poll closed since it was checked above.
+	if voted == 0 {
+		respondWithError(w, http.StatusBadRequest, "Invalid poll option", nil)
+		return
+	}
+
+	chirps := []Chirp{chirpFromDB(chirp)}
+	err = cfg.setPolls(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load poll", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0].Poll)
+}
+
+// setPolls attaches each chirp's poll, revealing the results of polls that
+// have closed or that the authenticated caller has voted in.
+func (cfg *apiConfig) setPolls(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	chirpIDs := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		chirpIDs = append(chirpIDs, chirp.ID)
+	}
+
+	rows, err := cfg.db.GetPollOptionsForChirps(ctx, chirpIDs)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	byChirp := map[uuid.UUID]*Poll{}
+	pollIDs := []uuid.UUID{}
+	for _, row := range rows {
+		poll, ok := byChirp[row.ChirpID]
+		if !ok {
+			poll = &Poll{
+				ClosesAt:   row.ClosesAt,
+				Closed:     !row.ClosesAt.After(now),
+				Options:    []PollOption{},
+				TotalVotes: new(int32),
+			}
+			byChirp[row.ChirpID] = poll
+			pollIDs = append(pollIDs, row.ChirpID)
+		}
+
+		votes := row.VoteCount
+		poll.Options = append(poll.Options, PollOption{
+			ID:    row.ID,
+			Label: row.Label,
+			Votes: &votes,
+		})
+		*poll.TotalVotes += votes
+	}
+
+	if userID, ok := getUserID(ctx); ok {
+		votes, err := cfg.db.GetPollVotes(ctx, database.GetPollVotesParams{
+			UserID:   userID,
+			ChirpIds: pollIDs,
+		})
+		if err != nil {
+			return err
+		}
+		for _, vote := range votes {
+			byChirp[vote.ChirpID].VotedOptionID = uuid.NullUUID{UUID: vote.OptionID, Valid: true}
+		}
+	}
+
+	for _, poll := range byChirp {
+		if poll.Closed || poll.VotedOptionID.Valid {
+			continue
+		}
+		poll.TotalVotes = nil
+		for i := range poll.Options {
+			poll.Options[i].Votes = nil
+		}
+	}
+
+	for i := range chirps {
+		if poll, ok := byChirp[chirps[i].ID]; ok {
+			chirps[i].Poll = poll
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/moderation"
+)
+
+func TestPollOptionsAreModerated(t *testing.T) {
+	testCases := []struct {
+		name        string
+		option      string
+		want        int
+		wantLabel   string
+		wantFlagged []string
+	}{
+		{name: "clean", option: "ramen", want: http.StatusCreated, wantLabel: "ramen"},
+		{name: "blocked", option: "fornax", want: http.StatusBadRequest},
+		{name: "masked", option: "darn ramen", want: http.StatusCreated, wantLabel: "**** ramen"},
+		{name: "flagged", option: "sus ramen", want: http.StatusCreated, wantLabel: "sus ramen", wantFlagged: []string{"sus"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			err := cfg.wordList.Replace([]moderation.Rule{
+				{Word: "fornax", Action: moderation.ActionBlock},
+				{Word: "darn", Action: moderation.ActionMask},
+				{Word: "sus", Action: moderation.ActionFlag},
+			})
+			if err != nil {
+				t.Fatalf("setting word list: %v", err)
+			}
+
+			_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+			rec := serveTestRequest(t, "POST /api/chirps", cfg.middlewareAuth(cfg.handlerCreateChirp), "/api/chirps", authorToken, map[string]any{
+				"body": "lunch?",
+				"poll": map[string]any{
+					"options":   []string{"tacos", tc.option},
+					"closes_at": time.Now().Add(time.Hour),
+				},
+			})
+			var chirp Chirp
+			if tc.want != http.StatusCreated {
+				expectStatus(t, rec, tc.want, nil)
+				return
+			}
+			expectStatus(t, rec, tc.want, &chirp)
+
+			if chirp.Poll == nil || len(chirp.Poll.Options) != 2 || chirp.Poll.Options[1].Label != tc.wantLabel {
+				t.Errorf("poll = %+v, want second option %q", chirp.Poll, tc.wantLabel)
+			}
+
+			flags, err := cfg.db.GetModerationFlags(t.Context(), database.GetModerationFlagsParams{Limit: 10})
+			if err != nil {
+				t.Fatalf("getting flags: %v", err)
+			}
+			var flagged []string
+			for _, flag := range flags {
+				if flag.ChirpID == chirp.ID {
+					flagged = append(flagged, flag.Words...)
+				}
+			}
+			if !slices.Equal(flagged, tc.wantFlagged) {
+				t.Errorf("flagged words = %v, want %v", flagged, tc.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestPollClosingIgnoresSessionTimeZone(t *testing.T) {
+	cfg := newTestConfigInTimeZone(t, testTimeZone)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, voterToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	chirp := createTestChirp(t, cfg, authorToken, map[string]any{
+		"body": "Tabs or spaces?",
+		"poll": map[string]any{
+			"options":   []string{"Tabs", "Spaces"},
+			"closes_at": time.Now().Add(time.Hour),
+		},
+	})
+
+	rec := serveTestRequest(t, "POST /api/chirps/{chirpID}/poll/votes", cfg.middlewareAuth(cfg.handlerVotePoll),
+		"/api/chirps/"+chirp.ID.String()+"/poll/votes", voterToken, map[string]any{"option_id": chirp.Poll.Options[0].ID})
+	expectStatus(t, rec, http.StatusOK, nil)
+}
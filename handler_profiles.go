@@ -0,0 +1,99 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/mentions"
+	"github.com/google/uuid"
+)
+
+// PublicUser is what anyone can see about a user. It never includes the
+// user's email or tokens.
+type PublicUser struct {
+	ID             uuid.UUID `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	Handle         string    `json:"handle,omitempty"`
+	DisplayName    string    `json:"display_name"`
+	Bio            string    `json:"bio"`
+	AvatarURL      string    `json:"avatar_url"`
+	FollowerCount  int64     `json:"follower_count"`
+	FollowingCount int64     `json:"following_count"`
+	ChirpCount     int64     `json:"chirp_count"`
+	IsChirpyRed    bool      `json:"is_chirpy_red"`
+	PinnedChirps   []Chirp   `json:"pinned_chirps"`
+}
+
+func (cfg *apiConfig) handlerGetUserProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	cfg.respondWithProfile(w, r, userID)
+}
+
+func (cfg *apiConfig) handlerGetUserProfileByHandle(w http.ResponseWriter, r *http.Request) {
+	handle, err := mentions.NormalizeHandle(r.PathValue("handle"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid handle", err)
+		return
+	}
+
+	user, err := cfg.db.GetUserByHandle(r.Context(), sql.NullString{String: handle, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	cfg.respondWithProfile(w, r, user.ID)
+}
+
+func (cfg *apiConfig) respondWithProfile(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	profile, err := cfg.db.GetUserProfile(r.Context(), database.GetUserProfileParams{
+		ViewerID: getViewerID(r.Context()),
+		ID:       userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	pinned, err := cfg.getPinnedChirps(r.Context(), profile.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get pinned chirps", err)
+		return
+	}
+
+	err = cfg.hydrateChirps(r.Context(), pinned)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, PublicUser{
+		ID:             profile.ID,
+		CreatedAt:      profile.CreatedAt,
+		Handle:         profile.Handle.String,
+		DisplayName:    profile.DisplayName,
+		Bio:            profile.Bio,
+		AvatarURL:      profile.AvatarUrl,
+		FollowerCount:  profile.FollowerCount,
+		FollowingCount: profile.FollowingCount,
+		ChirpCount:     profile.ChirpCount,
+		IsChirpyRed:    profile.IsChirpyRed,
+		PinnedChirps:   pinned,
+	})
+}
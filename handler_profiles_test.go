@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestProfileChirpCountRespectsVisibility(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	author, authorToken := createTestUser(t, cfg, "author", auth.RoleUser)
+	_, followerToken := createTestUser(t, cfg, "follower", auth.RoleUser)
+	_, friendToken := createTestUser(t, cfg, "friend", auth.RoleUser)
+
+	rec := serveTestRequest(t, "POST /api/users/{userID}/follow", cfg.middlewareAuth(cfg.handlerFollowUser),
+		"/api/users/"+author.ID.String()+"/follow", followerToken, nil)
+	if rec.Code >= 300 {
+		t.Fatalf("following author: status %d; body: %s", rec.Code, rec.Body)
+	}
+
+	createTestChirp(t, cfg, authorToken, map[string]any{"body": "hello world"})
+	createTestChirp(t, cfg, authorToken, map[string]any{"body": "just for followers", "visibility": visibilityFollowers})
+	createTestChirp(t, cfg, authorToken, map[string]any{"body": "psst @friend", "visibility": visibilityMentioned})
+
+	testCases := []struct {
+		name  string
+		token string
+		want  int64
+	}{
+		{name: "anonymous", token: "", want: 1},
+		{name: "follower", token: followerToken, want: 2},
+		{name: "mentioned", token: friendToken, want: 2},
+		{name: "author", token: authorToken, want: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := getTestProfile(t, cfg, author.ID, tc.token)
+			if profile.ChirpCount != tc.want {
+				t.Errorf("chirp_count = %d, want %d", profile.ChirpCount, tc.want)
+			}
+		})
+	}
+}
@@ -23,7 +23,17 @@ func (cfg *apiConfig) handlerRefreshToken(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	accessToken, err := auth.MakeJWT(user.ID, cfg.jwtSecret, time.Hour)
+	suspended, err := cfg.isSuspended(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check account status", err)
+		return
+	}
+	if suspended {
+		respondWithError(w, http.StatusForbidden, "Account suspended", nil)
+		return
+	}
+
+	accessToken, err := auth.MakeJWT(user.ID, auth.Role(user.Role), cfg.jwtSecret, time.Hour)
 
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "failed to create JWT", err)
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// Report is a complaint about a chirp or a user. UserID is the reported
+// user, which for chirp reports is the chirp's author.
+type Report struct {
+	ID         uuid.UUID      `json:"id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ReporterID uuid.UUID      `json:"reporter_id"`
+	UserID     uuid.UUID      `json:"user_id"`
+	ChirpID    uuid.NullUUID  `json:"chirp_id"`
+	Reason     string         `json:"reason"`
+	Details    string         `json:"details"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
+	Resolution string         `json:"resolution,omitempty"`
+	Actions    []ReportAction `json:"actions,omitempty"`
+}
+
+// ReportAction is a step taken on a report. RemovedBody is what the chirp
+// said when a remove_chirp action took it down.
+type ReportAction struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Action      string    `json:"action"`
+	Note        string    `json:"note"`
+	RemovedBody string    `json:"removed_body,omitempty"`
+}
+
+type reportsPage struct {
+	Reports    []Report `json:"reports"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+type reportParameters struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+func reportFromDB(report database.Report) Report {
+	r := Report{
+		ID:         report.ID,
+		CreatedAt:  report.CreatedAt,
+		ReporterID: report.ReporterID,
+		UserID:     report.UserID,
+		ChirpID:    report.ChirpID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Resolution: report.Resolution.String,
+	}
+	if report.ResolvedAt.Valid {
+		r.ResolvedAt = &report.ResolvedAt.Time
+	}
+	return r
+}
+
+func (cfg *apiConfig) handlerReportChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		return
+	}
+
+	if chirp.UserID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't report your own chirp", nil)
+		return
+	}
+
+	cfg.createReport(w, r, database.CreateReportParams{
+		ReporterID: userID,
+		UserID:     chirp.UserID,
+		ChirpID:    uuid.NullUUID{UUID: chirp.ID, Valid: true},
+	})
+}
+
+func (cfg *apiConfig) handlerReportUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	reportedID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	if reportedID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't report yourself", nil)
+		return
+	}
+
+	_, err = cfg.db.GetUserByID(r.Context(), reportedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	cfg.createReport(w, r, database.CreateReportParams{
+		ReporterID: userID,
+		UserID:     reportedID,
+	})
+}
+
+// createReport decodes the reason and details for a report on the target in
+// params and stores it. Each reporter can only report a target once.
+func (cfg *apiConfig) createReport(w http.ResponseWriter, r *http.Request, params database.CreateReportParams) {
+	decoder := json.NewDecoder(r.Body)
+	input := reportParameters{}
+	err := decoder.Decode(&input)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	params.Reason, err = parseReportReason(input.Reason)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid reason", err)
+		return
+	}
+
+	params.Details = strings.TrimSpace(input.Details)
+	err = validateReportDetails(params.Details)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid details", err)
+		return
+	}
+
+	report, err := cfg.db.CreateReport(r.Context(), params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "You've already reported that", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create report", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, reportFromDB(report))
+}
+
+// handlerGetReports is the moderation queue: open reports, oldest first, or
+// resolved ones with ?status=resolved.
+func (cfg *apiConfig) handlerGetReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var resolved bool
+	switch query.Get("status") {
+	case "", "open":
+	case "resolved":
+		resolved = true
+	default:
+		respondWithError(w, http.StatusBadRequest, "status must be open or resolved", nil)
+		return
+	}
+
+	page, err := pagination.ParseParams(query)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid pagination parameters", err)
+		return
+	}
+
+	rows, err := cfg.db.GetReports(r.Context(), database.GetReportsParams{
+		Resolved:        resolved,
+		CursorCreatedAt: page.CursorCreatedAt,
+		CursorID:        page.CursorID,
+		Limit:           page.Limit + 1,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get reports", err)
+		return
+	}
+
+	rows, next := pagination.Trim(rows, page.Limit, func(report database.Report) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: report.CreatedAt, ID: report.ID}
+	})
+
+	reports := reportsPage{Reports: []Report{}, NextCursor: next}
+	reportIDs := []uuid.UUID{}
+	for _, row := range rows {
+		reports.Reports = append(reports.Reports, reportFromDB(row))
+		reportIDs = append(reportIDs, row.ID)
+	}
+
+	if resolved && len(reportIDs) > 0 {
+		actions, err := cfg.db.GetReportActions(r.Context(), reportIDs)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to get report actions", err)
+			return
+		}
+
+		byReport := map[uuid.UUID][]ReportAction{}
+		for _, action := range actions {
+			byReport[action.ReportID] = append(byReport[action.ReportID], ReportAction{
+				CreatedAt:   action.CreatedAt,
+				Action:      action.Action,
+				Note:        action.Note,
+				RemovedBody: action.RemovedBody,
+			})
+		}
+		for i := range reports.Reports {
+			reports.Reports[i].Actions = byReport[reports.Reports[i].ID]
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, reports)
+}
+
+// handlerResolveReport closes a report by dismissing it, removing the
+// reported chirp or suspending the reported user. Removing a chirp also
+// resolves every other open report about it, and suspending a user every
+// other open report about them. Each resolved report records the action.
+func (cfg *apiConfig) handlerResolveReport(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Action string `json:"action"`
+		Note   string `json:"note"`
+	}
+
+	reportID, err := uuid.Parse(r.PathValue("reportID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid report ID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	action, err := parseResolution(params.Action)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid action", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to resolve report", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	report, err := qtx.GetReportForUpdate(r.Context(), reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Report not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get report", err)
+		return
+	}
+
+	if report.ResolvedAt.Valid {
+		respondWithError(w, http.StatusConflict, "Report has already been resolved", nil)
+		return
+	}
+
+	resolve := database.ResolveReportsParams{
+		Resolution: sql.NullString{String: action, Valid: true},
+		ID:         report.ID,
+	}
+
+	var blobs []database.DeleteChirpMediaRow
+	var removedBody string
+	switch action {
+	case resolutionRemoveChirp:
+		if !report.ChirpID.Valid {
+			respondWithError(w, http.StatusBadRequest, "Only chirp reports can remove a chirp", nil)
+			return
+		}
+
+		// The author can always see their own chirp, so it's only missing if
+		// it has already been deleted.
+		chirp, err := qtx.GetChirp(r.Context(), database.GetChirpParams{
+			ID:       report.ChirpID.UUID,
+			ViewerID: uuid.NullUUID{UUID: report.UserID, Valid: true},
+		})
+		if err == nil {
+			removedBody = chirp.Body
+			blobs, err = deleteChirpRows(r.Context(), qtx, chirp)
+			if err == nil {
+				err = recordAudit(r.Context(), qtx, auditEntry{
+					Action:     auditActionRemoveChirp,
+					TargetType: auditTargetChirp,
+					TargetID:   chirp.ID.String(),
+					Before:     chirpFromDB(chirp),
+				})
+			}
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Failed to remove chirp", err)
+			return
+		}
+		resolve.ChirpID = report.ChirpID
+
+	case resolutionSuspendUser:
+		before, err := qtx.GetUserByID(r.Context(), report.UserID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+			return
+		}
+
+		err = checkCanSuspend(r.Context(), before)
+		if err != nil {
+			respondWithError(w, http.StatusForbidden, "You can't suspend that user", err)
+			return
+		}
+
+		user, err := qtx.SuspendUser(r.Context(), database.SuspendUserParams{
+			ID:               report.UserID,
+			SuspensionReason: report.Reason,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to suspend user", err)
+			return
+		}
+
+		err = recordAudit(r.Context(), qtx, auditEntry{
+			Action:     auditActionSuspendUser,
+			TargetType: auditTargetUser,
+			TargetID:   report.UserID.String(),
+			Before:     userAuditStateFromDB(before),
+			After:      userAuditStateFromDB(user),
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to record suspension", err)
+			return
+		}
+
+		err = qtx.RevokeUserTokens(r.Context(), report.UserID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to revoke user's tokens", err)
+			return
+		}
+		resolve.UserID = uuid.NullUUID{UUID: report.UserID, Valid: true}
+	}
+
+	resolvedIDs, err := qtx.ResolveReports(r.Context(), resolve)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to resolve report", err)
+		return
+	}
+
+	err = qtx.CreateReportActions(r.Context(), database.CreateReportActionsParams{
+		ReportIds:   resolvedIDs,
+		Action:      action,
+		Note:        strings.TrimSpace(params.Note),
+		RemovedBody: removedBody,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record report action", err)
+		return
+	}
+
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionResolveReport,
+		TargetType: auditTargetReport,
+		TargetID:   report.ID.String(),
+		Before:     reportFromDB(report),
+		After: struct {
+			Resolution        string      `json:"resolution"`
+			Note              string      `json:"note"`
+			ResolvedReportIDs []uuid.UUID `json:"resolved_report_ids"`
+		}{action, strings.TrimSpace(params.Note), resolvedIDs},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record report action", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to resolve report", err)
+		return
+	}
+
+	cfg.deleteChirpBlobs(r.Context(), blobs)
+
+	type response struct {
+		ResolvedReportIDs []uuid.UUID `json:"resolved_report_ids"`
+	}
+	respondWithJSON(w, http.StatusOK, response{ResolvedReportIDs: resolvedIDs})
+}
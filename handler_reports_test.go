@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/google/uuid"
+)
+
+func TestResolveRemoveChirpKeepsReport(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, reporterToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, moderatorToken := createTestUser(t, cfg, "", auth.RoleModerator)
+
+	chirp := createTestChirp(t, cfg, authorToken, map[string]any{"body": "buy followers now"})
+
+	rec := serveTestRequest(t, "POST /api/chirps/{chirpID}/reports", cfg.middlewareAuth(cfg.handlerReportChirp),
+		"/api/chirps/"+chirp.ID.String()+"/reports", reporterToken, map[string]any{"reason": "spam"})
+	var report Report
+	expectStatus(t, rec, http.StatusCreated, &report)
+
+	rec = serveTestRequest(t, "POST /admin/reports/{reportID}/resolve", cfg.middlewareRequireRole(auth.RoleModerator, cfg.handlerResolveReport),
+		"/admin/reports/"+report.ID.String()+"/resolve", moderatorToken, map[string]any{"action": resolutionRemoveChirp, "note": "spam"})
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	rec = serveTestRequest(t, "GET /api/chirps/{chirpID}", cfg.middlewareOptionalAuth(cfg.handlerGetChirp),
+		"/api/chirps/"+chirp.ID.String(), "", nil)
+	expectStatus(t, rec, http.StatusNotFound, nil)
+
+	stored, err := cfg.db.GetReportForUpdate(t.Context(), report.ID)
+	if err != nil {
+		t.Fatalf("report is gone after removing its chirp: %v", err)
+	}
+	if !stored.ResolvedAt.Valid || stored.Resolution.String != resolutionRemoveChirp {
+		t.Errorf("report resolution = %q (resolved %v), want %q", stored.Resolution.String, stored.ResolvedAt.Valid, resolutionRemoveChirp)
+	}
+	if stored.ChirpID.UUID != chirp.ID {
+		t.Errorf("report chirp = %v, want %v", stored.ChirpID.UUID, chirp.ID)
+	}
+
+	actions, err := cfg.db.GetReportActions(t.Context(), []uuid.UUID{report.ID})
+	if err != nil {
+		t.Fatalf("getting report actions: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Action != resolutionRemoveChirp || actions[0].Note != "spam" {
+		t.Errorf("report actions = %+v, want one %q action", actions, resolutionRemoveChirp)
+	}
+
+	rec = serveTestRequest(t, "GET /admin/reports", cfg.middlewareRequireRole(auth.RoleModerator, cfg.handlerGetReports),
+		"/admin/reports?status=resolved", moderatorToken, nil)
+	var page reportsPage
+	expectStatus(t, rec, http.StatusOK, &page)
+	if len(page.Reports) != 1 || len(page.Reports[0].Actions) != 1 || page.Reports[0].Actions[0].RemovedBody != "buy followers now" {
+		t.Errorf("resolved queue = %+v, want the removed chirp's body on its action", page.Reports)
+	}
+}
+
+func TestResolveSuspendUserChecksRank(t *testing.T) {
+	testCases := []struct {
+		name       string
+		targetRole auth.Role
+		self       bool
+		want       int
+	}{
+		{name: "user", targetRole: auth.RoleUser, want: http.StatusOK},
+		{name: "fellow moderator", targetRole: auth.RoleModerator, want: http.StatusForbidden},
+		{name: "admin", targetRole: auth.RoleAdmin, want: http.StatusForbidden},
+		{name: "themselves", self: true, want: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+
+			moderator, moderatorToken := createTestUser(t, cfg, "", auth.RoleModerator)
+			_, reporterToken := createTestUser(t, cfg, "", auth.RoleUser)
+			target := moderator
+			if !tc.self {
+				target, _ = createTestUser(t, cfg, "", tc.targetRole)
+			}
+
+			rec := serveTestRequest(t, "POST /api/users/{userID}/reports", cfg.middlewareAuth(cfg.handlerReportUser),
+				"/api/users/"+target.ID.String()+"/reports", reporterToken, map[string]any{"reason": "harassment"})
+			var report Report
+			expectStatus(t, rec, http.StatusCreated, &report)
+
+			rec = serveTestRequest(t, "POST /admin/reports/{reportID}/resolve", cfg.middlewareRequireRole(auth.RoleModerator, cfg.handlerResolveReport),
+				"/admin/reports/"+report.ID.String()+"/resolve", moderatorToken, map[string]any{"action": resolutionSuspendUser})
+			expectStatus(t, rec, tc.want, nil)
+
+			suspended, err := cfg.isSuspended(t.Context(), target.ID)
+			if err != nil {
+				t.Fatalf("checking suspension: %v", err)
+			}
+			if suspended != (tc.want == http.StatusOK) {
+				t.Errorf("suspended = %v after a %d response", suspended, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerSetUserRole makes a user a plain user, a moderator or an admin.
+// Privileged routes check the current role, so the change applies to the
+// user's next request.
+func (cfg *apiConfig) handlerSetUserRole(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Role string `json:"role"`
+	}
+	type response struct {
+		UserID uuid.UUID `json:"user_id"`
+		Role   string    `json:"role"`
+	}
+
+	adminID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	// Admins can't demote themselves, so there's always at least one.
+	if userID == adminID {
+		respondWithError(w, http.StatusBadRequest, "You can't change your own role", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	role, err := auth.ParseRole(params.Role)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid role", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to set role", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	before, err := qtx.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	user, err := qtx.SetUserRole(r.Context(), database.SetUserRoleParams{
+		ID:   userID,
+		Role: string(role),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to set role", err)
+		return
+	}
+
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionSetRole,
+		TargetType: auditTargetUser,
+		TargetID:   userID.String(),
+		Before:     userAuditStateFromDB(before),
+		After:      userAuditStateFromDB(user),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record role change", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to set role", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response{UserID: user.ID, Role: user.Role})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestDemotedAdminIsRefused(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, adminToken := createTestUser(t, cfg, "", auth.RoleAdmin)
+	demoted, demotedToken := createTestUser(t, cfg, "", auth.RoleAdmin)
+
+	getAudit := cfg.middlewareRequireRole(auth.RoleAdmin, cfg.handlerGetAuditLog)
+
+	rec := serveTestRequest(t, "GET /admin/audit", getAudit, "/admin/audit", demotedToken, nil)
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	rec = serveTestRequest(t, "PUT /admin/users/{userID}/role", cfg.middlewareRequireRole(auth.RoleAdmin, cfg.handlerSetUserRole),
+		"/admin/users/"+demoted.ID.String()+"/role", adminToken, map[string]any{"role": auth.RoleUser})
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	// The token still says admin, but the role it was issued with is gone.
+	rec = serveTestRequest(t, "GET /admin/audit", getAudit, "/admin/audit", demotedToken, nil)
+	expectStatus(t, rec, http.StatusForbidden, nil)
+}
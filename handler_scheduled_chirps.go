@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// parsePublishAt checks that a requested publish time is in the future.
+func parsePublishAt(publishAt time.Time) (sql.NullTime, error) {
+	if !publishAt.After(time.Now()) {
+		return sql.NullTime{}, errors.New("publish_at must be in the future")
+	}
+	return sql.NullTime{Time: publishAt, Valid: true}, nil
+}
+
+func (cfg *apiConfig) handlerGetScheduledChirps(w http.ResponseWriter, r *http.Request) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	dbChirps, err := cfg.db.GetScheduledChirps(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get scheduled chirps", err)
+		return
+	}
+
+	chirps := make([]Chirp, 0, len(dbChirps))
+	for _, chirp := range dbChirps {
+		chirps = append(chirps, chirpFromDB(chirp))
+	}
+
+	err = cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps)
+}
+
+func (cfg *apiConfig) handlerRescheduleChirp(w http.ResponseWriter, r *http.Request) {
+	chirp, ok := cfg.getOwnScheduledChirp(w, r)
+	if !ok {
+		return
+	}
+
+	type parameters struct {
+		PublishAt *time.Time `json:"publish_at"`
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err := decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	if params.PublishAt == nil {
+		respondWithError(w, http.StatusBadRequest, "publish_at is required", nil)
+		return
+	}
+	scheduledFor, err := parsePublishAt(*params.PublishAt)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid publish time", err)
+		return
+	}
+
+	updated, err := cfg.db.RescheduleChirp(r.Context(), database.RescheduleChirpParams{
+		ID:           chirp.ID,
+		ScheduledFor: scheduledFor,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Chirp has already been published", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to reschedule chirp", err)
+		return
+	}
+
+	chirps := []Chirp{chirpFromDB(updated)}
+	err = cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
+
+func (cfg *apiConfig) handlerCancelScheduledChirp(w http.ResponseWriter, r *http.Request) {
+	chirp, ok := cfg.getOwnScheduledChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.cancelScheduledChirp(r.Context(), chirp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Chirp has already been published", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to cancel chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getOwnScheduledChirp loads the pending chirp named in the path and checks
+// the caller wrote it, responding with an error if not.
+func (cfg *apiConfig) getOwnScheduledChirp(w http.ResponseWriter, r *http.Request) (database.Chirp, bool) {
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return database.Chirp{}, false
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return database.Chirp{}, false
+	}
+
+	chirp, err := cfg.db.GetScheduledChirp(r.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Scheduled chirp not found", err)
+			return database.Chirp{}, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get scheduled chirp", err)
+		return database.Chirp{}, false
+	}
+
+	if chirp.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You don't own that chirp", nil)
+		return database.Chirp{}, false
+	}
+
+	return chirp, true
+}
+
+// cancelScheduledChirp deletes a chirp that hasn't been published yet along
+// with its media. It returns sql.ErrNoRows if the scheduler got there first.
+func (cfg *apiConfig) cancelScheduledChirp(ctx context.Context, chirp database.Chirp) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	blobs, err := qtx.DeleteChirpMedia(ctx, uuid.NullUUID{UUID: chirp.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	deleted, err := qtx.DeleteScheduledChirp(ctx, chirp.ID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	for _, blob := range blobs {
+		cfg.deleteBlobs(ctx, blob.BlobKey, blob.ThumbnailKey)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/pagination"
+	"github.com/JoeVinten/chirpy/internal/search"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerSearchChirps(w http.ResponseWriter, r *http.Request) {
+	// Snippet is HTML: the chirp's text, escaped, with the matching words
+	// wrapped in <mark> tags.
+	type searchResult struct {
+		Chirp
+		Rank    float32 `json:"rank"`
+		Snippet string  `json:"snippet"`
+	}
+
+	query := r.URL.Query()
+
+	tsQuery, err := search.BuildQuery(query.Get("q"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid search query", err)
+		return
+	}
+
+	limit, err := pagination.ParseLimit(query.Get("limit"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid limit", err)
+		return
+	}
+
+	var authorID uuid.NullUUID
+	if authorIDStr := query.Get("author_id"); authorIDStr != "" {
+		uID, err := uuid.Parse(authorIDStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid user id", err)
+			return
+		}
+		authorID = uuid.NullUUID{UUID: uID, Valid: true}
+	}
+
+	rows, err := cfg.db.SearchChirps(r.Context(), database.SearchChirpsParams{
+		Query:    tsQuery,
+		AuthorID: authorID,
+		ViewerID: getViewerID(r.Context()),
+		Limit:    limit,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to search chirps", err)
+		return
+	}
+
+	chirps := []Chirp{}
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row.Chirp))
+	}
+	err = cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	results := []searchResult{}
+	for i, row := range rows {
+		results = append(results, searchResult{
+			Chirp:   chirps[i],
+			Rank:    row.Rank,
+			Snippet: row.Snippet,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestSearchSnippetEscapesBody(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, token := createTestUser(t, cfg, "", auth.RoleUser)
+	createTestChirp(t, cfg, token, map[string]any{"body": `<img src=x onerror="alert(1)"> pancakes & syrup`})
+
+	rec := serveTestRequest(t, "GET /api/chirps/search", cfg.middlewareOptionalAuth(cfg.handlerSearchChirps),
+		"/api/chirps/search?q=pancakes", "", nil)
+	var results []json.RawMessage
+	expectStatus(t, rec, http.StatusOK, &results)
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	var result struct {
+		Snippet string `json:"snippet"`
+	}
+	err := json.Unmarshal(results[0], &result)
+	if err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+
+	if strings.Contains(result.Snippet, "<img") || strings.Contains(result.Snippet, `"alert`) {
+		t.Errorf("snippet %q contains unescaped HTML from the body", result.Snippet)
+	}
+	if !strings.Contains(result.Snippet, "<mark>pancakes</mark>") {
+		t.Errorf("snippet %q doesn't highlight the match", result.Snippet)
+	}
+	if !strings.Contains(result.Snippet, "&amp;") {
+		t.Errorf("snippet %q doesn't escape &", result.Snippet)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const maxSuspensionReasonLength = 500
+
+type Suspension struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	SuspendedAt    time.Time  `json:"suspended_at"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	Reason         string     `json:"reason"`
+}
+
+// isSuspended reports whether a user is serving a suspension right now.
+// Suspensions with an end time lapse on their own once it passes.
+func (cfg *apiConfig) isSuspended(ctx context.Context, userID uuid.UUID) (bool, error) {
+	_, err := cfg.db.GetActiveSuspension(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkCanSuspend returns an error if the caller isn't allowed to suspend
+// target, or to lift their suspension. Nobody can suspend themselves, and
+// staff can only be suspended by someone who outranks them.
+func checkCanSuspend(ctx context.Context, target database.User) error {
+	userID, _ := getUserID(ctx)
+	if target.ID == userID {
+		return errors.New("you can't suspend yourself")
+	}
+	if !getRole(ctx).CanSuspend(auth.Role(target.Role)) {
+		return errors.New("staff can only be suspended by someone who outranks them")
+	}
+	return nil
+}
+
+// handlerSuspendUser suspends a user, until a given time or indefinitely.
+// Their refresh tokens are revoked and their access tokens stop working at
+// once. Suspending someone already suspended replaces the suspension.
+func (cfg *apiConfig) handlerSuspendUser(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Reason string     `json:"reason"`
+		Until  *time.Time `json:"until"`
+	}
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	reason := strings.TrimSpace(params.Reason)
+	if reason == "" {
+		respondWithError(w, http.StatusBadRequest, "A reason is required", nil)
+		return
+	}
+	if utf8.RuneCountInString(reason) > maxSuspensionReasonLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Reason can be at most %d characters", maxSuspensionReasonLength), nil)
+		return
+	}
+
+	var until sql.NullTime
+	if params.Until != nil {
+		if !params.Until.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "until must be in the future", nil)
+			return
+		}
+		until = sql.NullTime{Time: *params.Until, Valid: true}
+	}
+
+	target, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	err = checkCanSuspend(r.Context(), target)
+	if err != nil {
+		respondWithError(w, http.StatusForbidden, "You can't suspend that user", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to suspend user", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	user, err := qtx.SuspendUser(r.Context(), database.SuspendUserParams{
+		ID:               userID,
+		SuspendedUntil:   until,
+		SuspensionReason: reason,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to suspend user", err)
+		return
+	}
+
+	err = qtx.RevokeUserTokens(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke user's tokens", err)
+		return
+	}
+
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionSuspendUser,
+		TargetType: auditTargetUser,
+		TargetID:   userID.String(),
+		Before:     userAuditStateFromDB(target),
+		After:      userAuditStateFromDB(user),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record suspension", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to suspend user", err)
+		return
+	}
+
+	suspension := Suspension{
+		UserID:      user.ID,
+		SuspendedAt: user.SuspendedAt.Time,
+		Reason:      user.SuspensionReason,
+	}
+	if user.SuspendedUntil.Valid {
+		suspension.SuspendedUntil = &user.SuspendedUntil.Time
+	}
+	respondWithJSON(w, http.StatusOK, suspension)
+}
+
+// handlerUnsuspendUser lifts a user's suspension. Their revoked refresh
+// tokens stay revoked, so they need to log in again.
+func (cfg *apiConfig) handlerUnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unsuspend user", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	before, err := qtx.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "user was not found", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	err = checkCanSuspend(r.Context(), before)
+	if err != nil {
+		respondWithError(w, http.StatusForbidden, "You can't unsuspend that user", err)
+		return
+	}
+
+	user, err := qtx.UnsuspendUser(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unsuspend user", err)
+		return
+	}
+
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionUnsuspendUser,
+		TargetType: auditTargetUser,
+		TargetID:   userID.String(),
+		Before:     userAuditStateFromDB(before),
+		After:      userAuditStateFromDB(user),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record unsuspension", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to unsuspend user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
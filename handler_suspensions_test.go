@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+)
+
+func TestUnsuspendUserChecksRank(t *testing.T) {
+	testCases := []struct {
+		name       string
+		callerRole auth.Role
+		targetRole auth.Role
+		want       int
+	}{
+		{name: "moderator lifts a user's suspension", callerRole: auth.RoleModerator, targetRole: auth.RoleUser, want: http.StatusOK},
+		{name: "moderator lifts a moderator's suspension", callerRole: auth.RoleModerator, targetRole: auth.RoleModerator, want: http.StatusForbidden},
+		{name: "moderator lifts an admin's suspension", callerRole: auth.RoleModerator, targetRole: auth.RoleAdmin, want: http.StatusForbidden},
+		{name: "admin lifts a moderator's suspension", callerRole: auth.RoleAdmin, targetRole: auth.RoleModerator, want: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+
+			_, callerToken := createTestUser(t, cfg, "", tc.callerRole)
+			target, _ := createTestUser(t, cfg, "", tc.targetRole)
+
+			// Nobody outranks an admin, so set the suspension up directly.
+			_, err := cfg.db.SuspendUser(t.Context(), database.SuspendUserParams{
+				ID:               target.ID,
+				SuspensionReason: "spam",
+			})
+			if err != nil {
+				t.Fatalf("suspending user: %v", err)
+			}
+
+			rec := serveTestRequest(t, "POST /admin/users/{userID}/unsuspend", cfg.middlewareRequireRole(auth.RoleModerator, cfg.handlerUnsuspendUser),
+				"/admin/users/"+target.ID.String()+"/unsuspend", callerToken, nil)
+			expectStatus(t, rec, tc.want, nil)
+
+			suspended, err := cfg.isSuspended(t.Context(), target.ID)
+			if err != nil {
+				t.Fatalf("checking suspension: %v", err)
+			}
+			if suspended != (tc.want != http.StatusOK) {
+				t.Errorf("suspended = %v after a %d response", suspended, rec.Code)
+			}
+		})
+	}
+}
+
+func TestTimedSuspensionIgnoresSessionTimeZone(t *testing.T) {
+	cfg := newTestConfigInTimeZone(t, testTimeZone)
+
+	_, moderatorToken := createTestUser(t, cfg, "", auth.RoleModerator)
+	target, _ := createTestUser(t, cfg, "", auth.RoleUser)
+
+	rec := serveTestRequest(t, "POST /admin/users/{userID}/suspend", cfg.middlewareRequireRole(auth.RoleModerator, cfg.handlerSuspendUser),
+		"/admin/users/"+target.ID.String()+"/suspend", moderatorToken, map[string]any{
+			"reason": "spam",
+			"until":  time.Now().Add(time.Hour),
+		})
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	suspended, err := cfg.isSuspended(t.Context(), target.ID)
+	if err != nil {
+		t.Fatalf("checking suspension: %v", err)
+	}
+	if !suspended {
+		t.Error("suspension lapsed before its until time")
+	}
+}
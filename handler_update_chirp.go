@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// Chirpy Red members can edit at any time; everyone else gets a short window.
+const chirpEditWindow = 30 * time.Minute
+
+// Once a poll has votes its chirp is locked, so the question can't be
+// rewritten under the people who answered it.
+var errPollHasVotes = errors.New("chirps can't be edited once their poll has votes")
+
+type ChirpRevision struct {
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (cfg *apiConfig) handlerUpdateChirp(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		return
+	}
+
+	userID, ok := getUserID(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User ID not found", nil)
+		return
+	}
+
+	if userID != chirp.UserID {
+		respondWithError(w, http.StatusForbidden, "You don't own that chirp", nil)
+		return
+	}
+
+	if chirp.RechirpOf.Valid {
+		respondWithError(w, http.StatusBadRequest, "Rechirps can't be edited", nil)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "database error getting user", err)
+		return
+	}
+
+	if !user.IsChirpyRed && time.Since(chirp.CreatedAt) > chirpEditWindow {
+		respondWithError(w, http.StatusForbidden, "Chirps can only be edited for 30 minutes without Chirpy Red", nil)
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	params := parameters{}
+	err = decoder.Decode(&params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	err = validateChirpBody(params.Body, cfg.maxChirpLengthFor(user))
+	if err != nil {
+		respondWithInvalidChirp(w, err)
+		return
+	}
+
+	verdict := cfg.moderator.Check(params.Body)
+	if verdict.IsBlocked() {
+		respondWithError(w, http.StatusBadRequest, "Chirp contains a blocked word", nil)
+		return
+	}
+
+	updated, err := cfg.updateChirpBody(r.Context(), database.UpdateChirpBodyParams{
+		ID:     chirp.ID,
+		UserID: userID,
+		Body:   verdict.Text,
+	}, verdict.Flagged)
+	if err != nil {
+		if errors.Is(err, errPollHasVotes) {
+			respondWithError(w, http.StatusConflict, "Chirp can't be edited", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update chirp", err)
+		return
+	}
+
+	chirps := []Chirp{chirpFromDB(updated)}
+	err = cfg.hydrateChirps(r.Context(), chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load chirp details", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, chirps[0])
+}
+
+func (cfg *apiConfig) updateChirpBody(ctx context.Context, params database.UpdateChirpBodyParams, flaggedWords []string) (database.Chirp, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	// Locking the poll holds off new votes until the edit commits, so the
+	// check below can't go stale.
+	err = qtx.LockPoll(ctx, params.ID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	hasVotes, err := qtx.PollHasVotes(ctx, params.ID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	if hasVotes {
+		return database.Chirp{}, errPollHasVotes
+	}
+
+	chirp, err := qtx.UpdateChirpBody(ctx, params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	if len(flaggedWords) > 0 {
+		err = qtx.FlagChirp(ctx, database.FlagChirpParams{
+			ChirpID: chirp.ID,
+			Words:   flaggedWords,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	err = indexChirpBody(ctx, qtx, chirp)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	return chirp, tx.Commit()
+}
+
+func (cfg *apiConfig) handlerGetChirpHistory(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(r.Context(), database.GetChirpParams{
+		ID:       chirpID,
+		ViewerID: getViewerID(r.Context()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Chirp not found", err)
+		return
+	}
+
+	revisions, err := cfg.db.GetChirpRevisions(r.Context(), chirp.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get chirp history", err)
+		return
+	}
+
+	// Older versions first, ending with the chirp as it reads now.
+	history := []ChirpRevision{}
+	for _, revision := range revisions {
+		history = append(history, ChirpRevision{
+			Body:      revision.Body,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+	history = append(history, ChirpRevision{
+		Body:      chirp.Body,
+		CreatedAt: chirp.UpdatedAt,
+	})
+
+	respondWithJSON(w, http.StatusOK, history)
+}
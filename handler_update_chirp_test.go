@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+func TestUpdateChirpWithVotedPoll(t *testing.T) {
+	cfg := newTestConfig(t)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+	_, voterToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	chirp := createTestChirp(t, cfg, authorToken, map[string]any{
+		"body": "Tabs or spaces?",
+		"poll": map[string]any{
+			"options":   []string{"Tabs", "Spaces"},
+			"closes_at": time.Now().Add(time.Hour),
+		},
+	})
+	if chirp.Poll == nil || len(chirp.Poll.Options) != 2 {
+		t.Fatalf("created chirp has poll %+v, want two options", chirp.Poll)
+	}
+
+	updateChirp := cfg.middlewareAuth(cfg.handlerUpdateChirp)
+	target := "/api/chirps/" + chirp.ID.String()
+
+	// Before anyone votes the author can still fix a typo.
+	rec := serveTestRequest(t, "PUT /api/chirps/{chirpID}", updateChirp, target, authorToken, map[string]any{"body": "Tabs or spaces??"})
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	rec = serveTestRequest(t, "POST /api/chirps/{chirpID}/poll/votes", cfg.middlewareAuth(cfg.handlerVotePoll),
+		target+"/poll/votes", voterToken, map[string]any{"option_id": chirp.Poll.Options[0].ID})
+	expectStatus(t, rec, http.StatusOK, nil)
+
+	rec = serveTestRequest(t, "PUT /api/chirps/{chirpID}", updateChirp, target, authorToken, map[string]any{"body": "Do you like bugs?"})
+	expectStatus(t, rec, http.StatusConflict, nil)
+
+	rec = serveTestRequest(t, "GET /api/chirps/{chirpID}", cfg.middlewareOptionalAuth(cfg.handlerGetChirp), target, "", nil)
+	var got Chirp
+	expectStatus(t, rec, http.StatusOK, &got)
+	if got.Body != "Tabs or spaces??" {
+		t.Errorf("body = %q after a refused edit, want %q", got.Body, "Tabs or spaces??")
+	}
+}
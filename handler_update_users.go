@@ -1,17 +1,25 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 
 	"github.com/JoeVinten/chirpy/internal/auth"
 	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/mentions"
+	"github.com/JoeVinten/chirpy/internal/profiles"
 )
 
 func (cfg *apiConfig) handlerUpdateAccount(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Handle   string `json:"handle"`
+		// Profile fields are left alone when omitted; send "" to clear one.
+		DisplayName *string `json:"display_name"`
+		Bio         *string `json:"bio"`
+		AvatarURL   *string `json:"avatar_url"`
 	}
 
 	userID, ok := getUserID(r.Context())
@@ -28,6 +36,34 @@ func (cfg *apiConfig) handlerUpdateAccount(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	var handle sql.NullString
+	if params.Handle != "" {
+		normalized, err := mentions.NormalizeHandle(params.Handle)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "invalid handle", err)
+			return
+		}
+		handle = sql.NullString{String: normalized, Valid: true}
+	}
+
+	displayName, err := normalizeProfileField(params.DisplayName, profiles.NormalizeDisplayName)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid display name", err)
+		return
+	}
+
+	bio, err := normalizeProfileField(params.Bio, profiles.NormalizeBio)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid bio", err)
+		return
+	}
+
+	avatarURL, err := normalizeProfileField(params.AvatarURL, profiles.NormalizeAvatarURL)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid avatar URL", err)
+		return
+	}
+
 	hashedPW, err := auth.HashPassword(params.Password)
 
 	if err != nil {
@@ -38,10 +74,18 @@ func (cfg *apiConfig) handlerUpdateAccount(w http.ResponseWriter, r *http.Reques
 	user, err := cfg.db.UpdateUsernamePassword(r.Context(), database.UpdateUsernamePasswordParams{
 		Email:          params.Email,
 		HashedPassword: hashedPW,
+		Handle:         handle,
+		DisplayName:    displayName,
+		Bio:            bio,
+		AvatarUrl:      avatarURL,
 		ID:             userID,
 	})
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			respondWithError(w, http.StatusConflict, "email or handle already in use", err)
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "Error updating the user", err)
 		return
 	}
@@ -52,6 +96,25 @@ func (cfg *apiConfig) handlerUpdateAccount(w http.ResponseWriter, r *http.Reques
 		UpdatedAt:   user.UpdatedAt,
 		Email:       user.Email,
 		IsChirpyRed: user.IsChirpyRed,
+		Handle:      user.Handle.String,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarUrl,
+		Role:        user.Role,
 	})
 
 }
+
+// normalizeProfileField validates an optional profile field. A nil value
+// means the field wasn't sent and should keep its current value.
+func normalizeProfileField(value *string, normalize func(string) (string, error)) (sql.NullString, error) {
+	if value == nil {
+		return sql.NullString{}, nil
+	}
+
+	normalized, err := normalize(*value)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: normalized, Valid: true}, nil
+}
@@ -43,12 +43,47 @@ func (cfg *apiConfig) handlerUpgradeUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = cfg.db.UpgradeUser(r.Context(), uID)
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to upgrade user", err)
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	before, err := qtx.GetUserByID(r.Context(), uID)
 	if err != nil {
 		respondWithError(w, http.StatusNotFound, "", err)
 		return
 	}
 
+	err = qtx.UpgradeUser(r.Context(), uID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to upgrade user", err)
+		return
+	}
+
+	after := before
+	after.IsChirpyRed = true
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionUpgradeUser,
+		TargetType: auditTargetUser,
+		TargetID:   uID.String(),
+		Before:     userAuditStateFromDB(before),
+		After:      userAuditStateFromDB(after),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to record upgrade", err)
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to upgrade user", err)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 
 }
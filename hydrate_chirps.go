@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// hydrateChirps fills in the parts of a Chirp response that don't live on the
+// chirp's own row: the shared chirp for rechirps and quotes, mentions,
+// attached media, polls, and whether the caller has liked each chirp.
+func (cfg *apiConfig) hydrateChirps(ctx context.Context, chirps []Chirp) error {
+	sharedIDs := []uuid.UUID{}
+	for _, chirp := range chirps {
+		if chirp.rechirpOfID.Valid {
+			sharedIDs = append(sharedIDs, chirp.rechirpOfID.UUID)
+		}
+		if chirp.quoteOfID.Valid {
+			sharedIDs = append(sharedIDs, chirp.quoteOfID.UUID)
+		}
+	}
+
+	shared := map[uuid.UUID]Chirp{}
+	if len(sharedIDs) > 0 {
+		rows, err := cfg.db.GetChirpsByIDs(ctx, database.GetChirpsByIDsParams{
+			Ids:      sharedIDs,
+			ViewerID: getViewerID(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		sharedChirps := []Chirp{}
+		for _, row := range rows {
+			sharedChirps = append(sharedChirps, chirpFromDB(row))
+		}
+		err = cfg.setChirpDetails(ctx, sharedChirps)
+		if err != nil {
+			return err
+		}
+
+		for _, chirp := range sharedChirps {
+			shared[chirp.ID] = chirp
+		}
+	}
+
+	for i := range chirps {
+		if original, ok := shared[chirps[i].rechirpOfID.UUID]; ok && chirps[i].rechirpOfID.Valid {
+			chirps[i].RechirpOf = &original
+		}
+		if original, ok := shared[chirps[i].quoteOfID.UUID]; ok && chirps[i].quoteOfID.Valid {
+			chirps[i].QuoteOf = &original
+		}
+	}
+
+	return cfg.setChirpDetails(ctx, chirps)
+}
+
+func (cfg *apiConfig) setChirpDetails(ctx context.Context, chirps []Chirp) error {
+	err := cfg.setMentions(ctx, chirps)
+	if err != nil {
+		return err
+	}
+	err = cfg.setMedia(ctx, chirps)
+	if err != nil {
+		return err
+	}
+	err = cfg.setPolls(ctx, chirps)
+	if err != nil {
+		return err
+	}
+	return cfg.setLikedByMe(ctx, chirps)
+}
+
+func (cfg *apiConfig) setMentions(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	chirpIDs := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		chirpIDs = append(chirpIDs, chirp.ID)
+	}
+
+	rows, err := cfg.db.GetChirpMentions(ctx, chirpIDs)
+	if err != nil {
+		return err
+	}
+
+	byChirp := map[uuid.UUID][]Mention{}
+	for _, row := range rows {
+		byChirp[row.ChirpID] = append(byChirp[row.ChirpID], Mention{
+			UserID: row.UserID,
+			Handle: row.Handle.String,
+		})
+	}
+
+	for i := range chirps {
+		if mentioned, ok := byChirp[chirps[i].ID]; ok {
+			chirps[i].Mentions = mentioned
+		}
+	}
+	return nil
+}
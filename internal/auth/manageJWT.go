@@ -15,14 +15,23 @@ const (
 	TokenTypeAccess TokenType = "chirpy"
 )
 
-func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
+// tokenClaims are the registered JWT claims plus the user's role.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Role Role `json:"role"`
+}
+
+func MakeJWT(userID uuid.UUID, role Role, tokenSecret string, expiresIn time.Duration) (string, error) {
 	secretKey := []byte(tokenSecret)
 
-	claims := &jwt.RegisteredClaims{
-		Issuer:    string(TokenTypeAccess),
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
-		Subject:   userID.String(),
+	claims := &tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    string(TokenTypeAccess),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+			Subject:   userID.String(),
+		},
+		Role: role,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -34,8 +43,10 @@ func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (str
 	return ss, err
 }
 
-func ValidateJWT(tokenString string, tokenSecret string) (uuid.UUID, error) {
-	claimsStruct := jwt.RegisteredClaims{}
+// ValidateJWT checks an access token and returns the user ID and role it was
+// issued for. Tokens minted before roles existed carry the user role.
+func ValidateJWT(tokenString string, tokenSecret string) (uuid.UUID, Role, error) {
+	claimsStruct := tokenClaims{}
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		&claimsStruct,
@@ -43,26 +54,34 @@ func ValidateJWT(tokenString string, tokenSecret string) (uuid.UUID, error) {
 	)
 
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, "", err
 	}
 
 	userIDString, err := token.Claims.GetSubject()
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, "", err
 	}
 
 	issuer, err := token.Claims.GetIssuer()
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, "", err
 	}
 	if issuer != string(TokenTypeAccess) {
-		return uuid.Nil, errors.New("invalid issuer")
+		return uuid.Nil, "", errors.New("invalid issuer")
 	}
 
 	id, err := uuid.Parse(userIDString)
 
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid user ID: %w", err)
+		return uuid.Nil, "", fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	if claimsStruct.Role == "" {
+		return id, RoleUser, nil
+	}
+	role, err := ParseRole(string(claimsStruct.Role))
+	if err != nil {
+		return uuid.Nil, "", err
 	}
-	return id, nil
+	return id, role, nil
 }
@@ -4,13 +4,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 func TestCreateAndVerifyJWT(t *testing.T) {
 	userID := uuid.New()
 	secret := "secret"
-	token, err := MakeJWT(userID, string(secret), 5*time.Second)
+	token, err := MakeJWT(userID, RoleModerator, string(secret), 5*time.Second)
 	if err != nil {
 		t.Fatalf("Error making token: %v", err)
 	}
@@ -19,7 +20,7 @@ func TestCreateAndVerifyJWT(t *testing.T) {
 		t.Fatalf("Token output is empty")
 	}
 
-	id, err := ValidateJWT(token, secret)
+	id, role, err := ValidateJWT(token, secret)
 	if err != nil {
 		t.Fatalf("Error verfiying token %v", err)
 	}
@@ -28,19 +29,23 @@ func TestCreateAndVerifyJWT(t *testing.T) {
 		t.Errorf("Wrong UserId in token. got %v, want %v", id, userID)
 	}
 
+	if role != RoleModerator {
+		t.Errorf("Wrong role in token. got %v, want %v", role, RoleModerator)
+	}
+
 }
 
 func TestTokenExpires(t *testing.T) {
 	userID := uuid.New()
 	secret := "secret"
-	token, err := MakeJWT(userID, string(secret), 1*time.Millisecond)
+	token, err := MakeJWT(userID, RoleUser, string(secret), 1*time.Millisecond)
 	if err != nil {
 		t.Fatalf("Error making token: %v", err)
 	}
 
 	time.Sleep(2 * time.Millisecond)
 
-	_, err = ValidateJWT(token, secret)
+	_, _, err = ValidateJWT(token, secret)
 	if err == nil {
 		t.Error("Token did not expire when it should have")
 	} else {
@@ -54,13 +59,34 @@ func TestJWTInvalidSecret(t *testing.T) {
 	invalidSecret := "invalidSecret"
 	duration := 5 * time.Minute
 
-	token, err := MakeJWT(userID, secret, duration)
+	token, err := MakeJWT(userID, RoleUser, secret, duration)
 	if err != nil {
 		t.Fatalf("Error making token: %v", err)
 	}
 
-	_, err = ValidateJWT(token, invalidSecret)
+	_, _, err = ValidateJWT(token, invalidSecret)
 	if err == nil {
 		t.Error("Token validation passed with an invalid secret")
 	}
 }
+
+func TestTokenWithoutRole(t *testing.T) {
+	userID := uuid.New()
+	secret := "secret"
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeAccess),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		Subject:   userID.String(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Error making token: %v", err)
+	}
+
+	_, role, err := ValidateJWT(token, secret)
+	if err != nil {
+		t.Fatalf("Error verfiying token %v", err)
+	}
+	if role != RoleUser {
+		t.Errorf("Wrong role for token without one. got %v, want %v", role, RoleUser)
+	}
+}
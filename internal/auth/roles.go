@@ -0,0 +1,49 @@
+package auth
+
+import "errors"
+
+// Role is what a user is allowed to do beyond using their own account.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// ParseRole checks role is one of user, moderator or admin.
+func ParseRole(role string) (Role, error) {
+	switch Role(role) {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return Role(role), nil
+	}
+	return "", errors.New("role must be user, moderator or admin")
+}
+
+// Includes reports whether r grants everything other does. Admins can do
+// anything moderators can, and moderators anything users can.
+func (r Role) Includes(other Role) bool {
+	return r.rank() >= other.rank()
+}
+
+// CanSuspend reports whether r may suspend a user with target. Moderators
+// and admins can suspend ordinary users, but staff can only be suspended by
+// someone who outranks them.
+func (r Role) CanSuspend(target Role) bool {
+	if !r.Includes(RoleModerator) {
+		return false
+	}
+	return !target.Includes(RoleModerator) || r.rank() > target.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 3
+	case RoleModerator:
+		return 2
+	case RoleUser:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package auth
+
+import "testing"
+
+func TestParseRole(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    Role
+		wantErr bool
+	}{
+		{input: "user", want: RoleUser},
+		{input: "moderator", want: RoleModerator},
+		{input: "admin", want: RoleAdmin},
+		{input: "", wantErr: true},
+		{input: "Admin", wantErr: true},
+		{input: "root", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseRole(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseRole(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseRole(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRoleIncludes(t *testing.T) {
+	testCases := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{role: RoleAdmin, required: RoleAdmin, want: true},
+		{role: RoleAdmin, required: RoleModerator, want: true},
+		{role: RoleAdmin, required: RoleUser, want: true},
+		{role: RoleModerator, required: RoleAdmin, want: false},
+		{role: RoleModerator, required: RoleModerator, want: true},
+		{role: RoleModerator, required: RoleUser, want: true},
+		{role: RoleUser, required: RoleModerator, want: false},
+		{role: RoleUser, required: RoleUser, want: true},
+		{role: Role("root"), required: RoleUser, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role)+"/"+string(tc.required), func(t *testing.T) {
+			if got := tc.role.Includes(tc.required); got != tc.want {
+				t.Errorf("%q.Includes(%q) = %v, want %v", tc.role, tc.required, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRoleCanSuspend(t *testing.T) {
+	testCases := []struct {
+		role   Role
+		target Role
+		want   bool
+	}{
+		{role: RoleAdmin, target: RoleUser, want: true},
+		{role: RoleAdmin, target: RoleModerator, want: true},
+		{role: RoleAdmin, target: RoleAdmin, want: false},
+		{role: RoleModerator, target: RoleUser, want: true},
+		{role: RoleModerator, target: RoleModerator, want: false},
+		{role: RoleModerator, target: RoleAdmin, want: false},
+		{role: RoleUser, target: RoleUser, want: false},
+		{role: Role(""), target: RoleUser, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.role)+"/"+string(tc.target), func(t *testing.T) {
+			if got := tc.role.CanSuspend(tc.target); got != tc.want {
+				t.Errorf("%q.CanSuspend(%q) = %v, want %v", tc.role, tc.target, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// URLWeight is how many characters a link counts as, however long it is.
+const URLWeight = 23
+
+// Length counts text the way a reader would: in user-perceived characters
+// rather than bytes or code points, with every http(s) link counting as
+// URLWeight characters.
+func Length(text string) int {
+	length := 0
+	for text != "" {
+		start := indexURL(text)
+		if start < 0 {
+			return length + CountGraphemes(text)
+		}
+
+		end := start + urlLength(text[start:])
+		length += CountGraphemes(text[:start]) + URLWeight
+		text = text[end:]
+	}
+	return length
+}
+
+// indexURL returns the index of the first http:// or https:// link in text,
+// or -1 if there isn't one. A bare scheme with nothing after it isn't a link.
+func indexURL(text string) int {
+	for i := 0; i < len(text); i++ {
+		for _, scheme := range []string{"http://", "https://"} {
+			if len(text)-i > len(scheme) && strings.EqualFold(text[i:i+len(scheme)], scheme) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// urlLength returns how many bytes of text, which starts with a link, belong
+// to the link. It runs to the next space, leaving off punctuation that more
+// likely ends the sentence than the link.
+func urlLength(text string) int {
+	end := strings.IndexFunc(text, unicode.IsSpace)
+	if end < 0 {
+		end = len(text)
+	}
+	return len(strings.TrimRight(text[:end], ".,:;!?'\")]"))
+}
+
+// CountGraphemes counts user-perceived characters: Unicode extended grapheme
+// clusters, so a letter with combining marks, a flag or a zero-width-joined
+// emoji sequence each count once.
+func CountGraphemes(text string) int {
+	return uniseg.GraphemeClusterCount(text)
+}
@@ -0,0 +1,67 @@
+package chirptext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountGraphemes(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "Empty", text: "", want: 0},
+		{name: "ASCII", text: "hello", want: 5},
+		{name: "Precomposed accent", text: "café", want: 4},
+		{name: "Combining accent", text: "café", want: 4},
+		{name: "Japanese", text: "こんにちは", want: 5},
+		{name: "Emoji", text: "😀😀", want: 2},
+		{name: "Skin tone", text: "👍🏽", want: 1},
+		{name: "Variation selector", text: "❤️", want: 1},
+		{name: "Zero-width joined family", text: "👨‍👩‍👧‍👦", want: 1},
+		{name: "Families side by side", text: "👨‍👩‍👧👩‍👩‍👦", want: 2},
+		{name: "Joined emoji with skin tones", text: "👩🏽‍💻🧑🏿‍🤝‍🧑🏻", want: 2},
+		{name: "Joiner between letters", text: "a\u200Db", want: 2},
+		{name: "Flags pair up", text: "🇬🇧🇫🇷", want: 2},
+		{name: "Odd regional indicator", text: "🇬🇧🇫", want: 2},
+		{name: "Flag after a letter", text: "a🇯🇵", want: 2},
+		{name: "Rainbow flag", text: "🏳️‍🌈", want: 1},
+		{name: "Subdivision flag", text: "🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F", want: 1},
+		{name: "Hangul jamo", text: "각", want: 1},
+		{name: "CRLF", text: "a\r\nb", want: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CountGraphemes(tc.text); got != tc.want {
+				t.Errorf("CountGraphemes(%q) = %d, want %d", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "No links", text: "hello world", want: 11},
+		{name: "Short link", text: "see https://x.co", want: 4 + URLWeight},
+		{name: "Long link", text: "https://example.com/" + strings.Repeat("a", 200), want: URLWeight},
+		{name: "Scheme is case-insensitive", text: "HTTP://example.com", want: URLWeight},
+		{name: "Trailing punctuation isn't part of the link", text: "(see https://example.com).", want: 5 + URLWeight + 2},
+		{name: "Two links", text: "http://a.com http://b.com", want: 2*URLWeight + 1},
+		{name: "Bare scheme", text: "https://", want: 8},
+		{name: "Emoji and a link", text: "👍🏽 https://example.com", want: 2 + URLWeight},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Length(tc.text); got != tc.want {
+				t.Errorf("Length(%q) = %d, want %d", tc.text, got, tc.want)
+			}
+		})
+	}
+}
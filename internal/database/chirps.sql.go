@@ -7,29 +7,63 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const chirpHasReplies = `-- name: ChirpHasReplies :one
+SELECT EXISTS (
+	SELECT 1 FROM chirps
+	WHERE in_reply_to = $1
+)
+`
+
+func (q *Queries) ChirpHasReplies(ctx context.Context, inReplyTo uuid.NullUUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, chirpHasReplies, inReplyTo)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, in_reply_to, rechirp_of, quote_of, scheduled_for, visibility)
 VALUES (
 	gen_random_uuid(),
 	NOW(),
 	NOW(),
 	$1,
-	$2
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body         string
+	UserID       uuid.UUID
+	InReplyTo    uuid.NullUUID
+	RechirpOf    uuid.NullUUID
+	QuoteOf      uuid.NullUUID
+	ScheduledFor sql.NullTime
+	Visibility   string
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.Body,
+		arg.UserID,
+		arg.InReplyTo,
+		arg.RechirpOf,
+		arg.QuoteOf,
+		arg.ScheduledFor,
+		arg.Visibility,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +71,18 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.InReplyTo,
+		&i.ReplyCount,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.RechirpCount,
+		&i.QuoteCount,
+		&i.EditedAt,
+		&i.ScheduledFor,
+		&i.Visibility,
 	)
 	return i, err
 }
@@ -56,13 +102,32 @@ func (q *Queries) DeleteChirp(ctx context.Context, arg DeleteChirpParams) error
 	return err
 }
 
+const deleteScheduledChirp = `-- name: DeleteScheduledChirp :execrows
+DELETE FROM chirps
+WHERE id = $1 AND scheduled_for IS NOT NULL
+`
+
+func (q *Queries) DeleteScheduledChirp(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteScheduledChirp, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-WHERE id=$1
+SELECT id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility FROM chirps
+WHERE id = $1 AND deleted_at IS NULL AND scheduled_for IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2)
 `
 
-func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, getChirp, id)
+type GetChirpParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) GetChirp(ctx context.Context, arg GetChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirp, arg.ID, arg.ViewerID)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -70,17 +135,142 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.InReplyTo,
+		&i.ReplyCount,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.RechirpCount,
+		&i.QuoteCount,
+		&i.EditedAt,
+		&i.ScheduledFor,
+		&i.Visibility,
 	)
 	return i, err
 }
 
-const getChirps = `-- name: GetChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-ORDER BY created_at
+const getChirpViewers = `-- name: GetChirpViewers :many
+SELECT viewer_id::uuid FROM chirps
+CROSS JOIN unnest($1::uuid[]) AS viewer_id
+WHERE chirps.id = $2
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, viewer_id)
+`
+
+type GetChirpViewersParams struct {
+	UserIds []uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) GetChirpViewers(ctx context.Context, arg GetChirpViewersParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpViewers, pq.Array(arg.UserIds), arg.ChirpID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var viewer_id uuid.UUID
+		if err := rows.Scan(&viewer_id); err != nil {
+			return nil, err
+		}
+		items = append(items, viewer_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsAsc = `-- name: GetChirpsAsc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility FROM chirps
+WHERE deleted_at IS NULL
+AND scheduled_for IS NULL
+AND ($1::uuid IS NULL OR user_id = $1::uuid)
+AND (
+	$2::timestamp IS NULL
+	OR (created_at, id) > ($2::timestamp, $3::uuid)
+)
+AND chirp_visible_to(id, user_id, visibility, $4)
+AND NOT chirp_muted_for(id, user_id, search_vector, $4)
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type GetChirpsAscParams struct {
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) GetChirpsAsc(ctx context.Context, arg GetChirpsAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsAsc,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.InReplyTo,
+			&i.ReplyCount,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.RechirpCount,
+			&i.QuoteCount,
+			&i.EditedAt,
+			&i.ScheduledFor,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpsByIDs = `-- name: GetChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility FROM chirps
+WHERE id = ANY($1::uuid[])
+AND deleted_at IS NULL
+AND scheduled_for IS NULL
+AND chirp_visible_to(id, user_id, visibility, $2)
 `
 
-func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirps)
+type GetChirpsByIDsParams struct {
+	Ids      []uuid.UUID
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) GetChirpsByIDs(ctx context.Context, arg GetChirpsByIDsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByIDs, pq.Array(arg.Ids), arg.ViewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +284,18 @@ func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.SearchVector,
+			&i.InReplyTo,
+			&i.ReplyCount,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.RechirpCount,
+			&i.QuoteCount,
+			&i.EditedAt,
+			&i.ScheduledFor,
+			&i.Visibility,
 		); err != nil {
 			return nil, err
 		}
@@ -108,14 +310,37 @@ func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
 	return items, nil
 }
 
-const getChirpsByUser = `-- name: GetChirpsByUser :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-WHERE user_id=$1
-ORDER BY created_at
+const getChirpsDesc = `-- name: GetChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility FROM chirps
+WHERE deleted_at IS NULL
+AND scheduled_for IS NULL
+AND ($1::uuid IS NULL OR user_id = $1::uuid)
+AND (
+	$2::timestamp IS NULL
+	OR (created_at, id) < ($2::timestamp, $3::uuid)
+)
+AND chirp_visible_to(id, user_id, visibility, $4)
+AND NOT chirp_muted_for(id, user_id, search_vector, $4)
+ORDER BY created_at DESC, id DESC
+LIMIT $5
 `
 
-func (q *Queries) GetChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirpsByUser, userID)
+type GetChirpsDescParams struct {
+	AuthorID        uuid.NullUUID
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+	ViewerID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) GetChirpsDesc(ctx context.Context, arg GetChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsDesc,
+		arg.AuthorID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +354,18 @@ func (q *Queries) GetChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chir
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.SearchVector,
+			&i.InReplyTo,
+			&i.ReplyCount,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.RechirpCount,
+			&i.QuoteCount,
+			&i.EditedAt,
+			&i.ScheduledFor,
+			&i.Visibility,
 		); err != nil {
 			return nil, err
 		}
@@ -142,3 +379,362 @@ func (q *Queries) GetChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chir
 	}
 	return items, nil
 }
+
+const getScheduledChirp = `-- name: GetScheduledChirp :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility FROM chirps
+WHERE id = $1 AND scheduled_for IS NOT NULL
+`
+
+func (q *Queries) GetScheduledChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getScheduledChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.InReplyTo,
+		&i.ReplyCount,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.RechirpCount,
+		&i.QuoteCount,
+		&i.EditedAt,
+		&i.ScheduledFor,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getScheduledChirps = `-- name: GetScheduledChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility FROM chirps
+WHERE user_id = $1
+AND scheduled_for IS NOT NULL
+ORDER BY scheduled_for ASC, id ASC
+`
+
+func (q *Queries) GetScheduledChirps(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getScheduledChirps, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.InReplyTo,
+			&i.ReplyCount,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.RechirpCount,
+			&i.QuoteCount,
+			&i.EditedAt,
+			&i.ScheduledFor,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getThread = `-- name: GetThread :many
+WITH RECURSIVE ancestors AS (
+	SELECT chirps.id, chirps.in_reply_to FROM chirps
+	WHERE chirps.id = $1
+	UNION ALL
+	SELECT chirps.id, chirps.in_reply_to FROM chirps
+	JOIN ancestors ON chirps.id = ancestors.in_reply_to
+),
+thread AS (
+	SELECT chirps.id, 0 AS depth FROM chirps
+	WHERE chirps.id = (SELECT ancestors.id FROM ancestors WHERE ancestors.in_reply_to IS NULL)
+	UNION ALL
+	SELECT chirps.id, thread.depth + 1 FROM chirps
+	JOIN thread ON chirps.in_reply_to = thread.id
+)
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.in_reply_to, chirps.reply_count, chirps.deleted_at, chirps.like_count, chirps.rechirp_of, chirps.quote_of, chirps.rechirp_count, chirps.quote_count, chirps.edited_at, chirps.scheduled_for, chirps.visibility, thread.depth::int AS depth
+FROM thread
+JOIN chirps ON chirps.id = thread.id
+WHERE chirps.scheduled_for IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $2)
+ORDER BY thread.depth, chirps.created_at, chirps.id
+`
+
+type GetThreadRow struct {
+	Chirp Chirp
+	Depth int32
+}
+
+type GetThreadParams struct {
+	ChirpID  uuid.UUID
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) GetThread(ctx context.Context, arg GetThreadParams) ([]GetThreadRow, error) {
+	rows, err := q.db.QueryContext(ctx, getThread, arg.ChirpID, arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetThreadRow
+	for rows.Next() {
+		var i GetThreadRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.SearchVector,
+			&i.Chirp.InReplyTo,
+			&i.Chirp.ReplyCount,
+			&i.Chirp.DeletedAt,
+			&i.Chirp.LikeCount,
+			&i.Chirp.RechirpOf,
+			&i.Chirp.QuoteOf,
+			&i.Chirp.RechirpCount,
+			&i.Chirp.QuoteCount,
+			&i.Chirp.EditedAt,
+			&i.Chirp.ScheduledFor,
+			&i.Chirp.Visibility,
+			&i.Depth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDueChirps = `-- name: PublishDueChirps :many
+UPDATE chirps SET scheduled_for = NULL,
+created_at = NOW(),
+updated_at = NOW()
+WHERE id IN (
+	SELECT due.id FROM chirps AS due
+	WHERE due.scheduled_for <= NOW()
+	ORDER BY due.scheduled_for
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility
+`
+
+func (q *Queries) PublishDueChirps(ctx context.Context, limit int32) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, publishDueChirps, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.InReplyTo,
+			&i.ReplyCount,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.RechirpCount,
+			&i.QuoteCount,
+			&i.EditedAt,
+			&i.ScheduledFor,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rescheduleChirp = `-- name: RescheduleChirp :one
+UPDATE chirps SET scheduled_for = $2,
+updated_at = NOW()
+WHERE id = $1 AND scheduled_for IS NOT NULL
+RETURNING id, created_at, updated_at, body, user_id, search_vector, in_reply_to, reply_count, deleted_at, like_count, rechirp_of, quote_of, rechirp_count, quote_count, edited_at, scheduled_for, visibility
+`
+
+type RescheduleChirpParams struct {
+	ID           uuid.UUID
+	ScheduledFor sql.NullTime
+}
+
+func (q *Queries) RescheduleChirp(ctx context.Context, arg RescheduleChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, rescheduleChirp, arg.ID, arg.ScheduledFor)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.InReplyTo,
+		&i.ReplyCount,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.RechirpCount,
+		&i.QuoteCount,
+		&i.EditedAt,
+		&i.ScheduledFor,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const searchChirps = `-- name: SearchChirps :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.in_reply_to, chirps.reply_count, chirps.deleted_at, chirps.like_count, chirps.rechirp_of, chirps.quote_of, chirps.rechirp_count, chirps.quote_count, chirps.edited_at, chirps.scheduled_for, chirps.visibility,
+	ts_rank(search_vector, to_tsquery('english', $1::text))::real AS rank,
+	ts_headline(
+		'english',
+		replace(replace(replace(replace(replace(body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'),
+		to_tsquery('english', $1::text),
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20'
+	)::text AS snippet
+FROM chirps
+WHERE deleted_at IS NULL
+AND scheduled_for IS NULL
+AND search_vector @@ to_tsquery('english', $1::text)
+AND ($2::uuid IS NULL OR user_id = $2::uuid)
+AND chirp_visible_to(id, user_id, visibility, $3)
+AND NOT chirp_muted_for(id, user_id, search_vector, $3)
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT $4
+`
+
+type SearchChirpsParams struct {
+	Query    string
+	AuthorID uuid.NullUUID
+	ViewerID uuid.NullUUID
+	Limit    int32
+}
+
+type SearchChirpsRow struct {
+	Chirp   Chirp
+	Rank    float32
+	Snippet string
+}
+
+func (q *Queries) SearchChirps(ctx context.Context, arg SearchChirpsParams) ([]SearchChirpsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirps,
+		arg.Query,
+		arg.AuthorID,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchChirpsRow
+	for rows.Next() {
+		var i SearchChirpsRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.SearchVector,
+			&i.Chirp.InReplyTo,
+			&i.Chirp.ReplyCount,
+			&i.Chirp.DeletedAt,
+			&i.Chirp.LikeCount,
+			&i.Chirp.RechirpOf,
+			&i.Chirp.QuoteOf,
+			&i.Chirp.RechirpCount,
+			&i.Chirp.QuoteCount,
+			&i.Chirp.EditedAt,
+			&i.Chirp.ScheduledFor,
+			&i.Chirp.Visibility,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const tombstoneChirp = `-- name: TombstoneChirp :exec
+WITH owned AS (
+	SELECT chirps.id FROM chirps
+	WHERE chirps.id = $1 AND chirps.user_id = $2
+),
+cleared_hashtags AS (
+	DELETE FROM chirp_hashtags
+	WHERE chirp_hashtags.chirp_id IN (SELECT owned.id FROM owned)
+),
+cleared_mentions AS (
+	DELETE FROM chirp_mentions
+	WHERE chirp_mentions.chirp_id IN (SELECT owned.id FROM owned)
+),
+cleared_poll AS (
+	DELETE FROM polls
+	WHERE polls.chirp_id IN (SELECT owned.id FROM owned)
+)
+UPDATE chirps SET body = '',
+deleted_at = NOW(),
+updated_at = NOW()
+WHERE id=$1 AND user_id=$2
+`
+
+type TombstoneChirpParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) TombstoneChirp(ctx context.Context, arg TombstoneChirpParams) error {
+	_, err := q.db.ExecContext(ctx, tombstoneChirp, arg.ID, arg.UserID)
+	return err
+}
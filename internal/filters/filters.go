@@ -0,0 +1,70 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/JoeVinten/chirpy/internal/hashtags"
+)
+
+// Kind is what a mute filter matches against.
+type Kind string
+
+const (
+	// KindWord matches a single word anywhere in a chirp.
+	KindWord Kind = "word"
+	// KindPhrase matches words appearing together in order.
+	KindPhrase Kind = "phrase"
+	// KindHashtag matches chirps tagged with a hashtag.
+	KindHashtag Kind = "hashtag"
+)
+
+const MaxValueLength = 100
+
+// ParseKind checks kind is one of word, phrase or hashtag.
+func ParseKind(kind string) (Kind, error) {
+	switch Kind(kind) {
+	case KindWord, KindPhrase, KindHashtag:
+		return Kind(kind), nil
+	}
+	return "", errors.New("kind must be word, phrase or hashtag")
+}
+
+// Normalize puts a filter's value into the form it's stored and matched in:
+// lowercase, with runs of spaces collapsed, and hashtags without their #.
+// Words must be a single word and phrases can't be longer than 100
+// characters.
+func Normalize(kind Kind, value string) (string, error) {
+	if !utf8.ValidString(value) {
+		return "", errors.New("value must be valid UTF-8")
+	}
+
+	if kind == KindHashtag {
+		tag, ok := hashtags.Normalize(strings.TrimSpace(value))
+		if !ok {
+			return "", errors.New("value must be a valid hashtag")
+		}
+		return tag, nil
+	}
+
+	words := strings.Fields(strings.ToLower(value))
+	if len(words) == 0 {
+		return "", errors.New("value can't be empty")
+	}
+	if kind == KindWord && len(words) > 1 {
+		return "", errors.New("a word filter can't contain spaces; use a phrase")
+	}
+
+	normalized := strings.Join(words, " ")
+	if utf8.RuneCountInString(normalized) > MaxValueLength {
+		return "", fmt.Errorf("value can be at most %d characters", MaxValueLength)
+	}
+	if strings.IndexFunc(normalized, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		return "", errors.New("value must contain a letter or digit")
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,68 @@
+package filters
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKind(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    Kind
+		wantErr bool
+	}{
+		{input: "word", want: KindWord},
+		{input: "phrase", want: KindPhrase},
+		{input: "hashtag", want: KindHashtag},
+		{input: "", wantErr: true},
+		{input: "Word", wantErr: true},
+		{input: "user", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseKind(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseKind(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseKind(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name    string
+		kind    Kind
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Word is lowercased and trimmed", kind: KindWord, input: "  Spoilers ", want: "spoilers"},
+		{name: "Word with spaces", kind: KindWord, input: "season finale", wantErr: true},
+		{name: "Empty word", kind: KindWord, input: "   ", wantErr: true},
+		{name: "Word of only punctuation", kind: KindWord, input: "?!", wantErr: true},
+		{name: "Phrase spaces are collapsed", kind: KindPhrase, input: " Season \t  Finale ", want: "season finale"},
+		{name: "Single word phrase", kind: KindPhrase, input: "finale", want: "finale"},
+		{name: "Longest phrase", kind: KindPhrase, input: strings.Repeat("a", MaxValueLength), want: strings.Repeat("a", MaxValueLength)},
+		{name: "Phrase too long", kind: KindPhrase, input: strings.Repeat("a", MaxValueLength+1), wantErr: true},
+		{name: "Hashtag loses its #", kind: KindHashtag, input: "#GoLang", want: "golang"},
+		{name: "Hashtag without #", kind: KindHashtag, input: "golang", want: "golang"},
+		{name: "Invalid hashtag", kind: KindHashtag, input: "#go lang", wantErr: true},
+		{name: "Invalid UTF-8", kind: KindWord, input: "spoil\xff", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.kind, tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Normalize(%q, %q) error = %v, wantErr %v", tc.kind, tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Normalize(%q, %q) = %q, want %q", tc.kind, tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package hashtags
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const MaxTagLength = 100
+
+// Extract returns the normalised, de-duplicated hashtags in a chirp body in
+// the order they first appear. A tag is a # followed by letters, digits or
+// underscores, must contain at least one letter, and can't be glued to the
+// end of another word (so "abc#def" isn't a tag).
+func Extract(body string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+
+	prev := ' '
+	for i := 0; i < len(body); {
+		r, size := utf8.DecodeRuneInString(body[i:])
+		if r != '#' || isTagRune(prev) {
+			prev = r
+			i += size
+			continue
+		}
+
+		end := i + size
+		prev = r
+		for end < len(body) {
+			next, nextSize := utf8.DecodeRuneInString(body[end:])
+			if !isTagRune(next) {
+				break
+			}
+			prev = next
+			end += nextSize
+		}
+
+		if tag, ok := Normalize(body[i+size : end]); ok && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+
+		i = end
+	}
+
+	return tags
+}
+
+// Normalize lowercases a tag (with or without its leading #) and reports
+// whether it is a valid hashtag.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", false
+	}
+
+	hasLetter := false
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return "", false
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+
+	return tag, hasLetter
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,95 @@
+package hashtags
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "No tags",
+			body: "just a normal chirp",
+			want: []string{},
+		},
+		{
+			name: "Tags are lowercased",
+			body: "Loving #GoLang and #SQL",
+			want: []string{"golang", "sql"},
+		},
+		{
+			name: "Duplicates are removed",
+			body: "#go #Go #GO",
+			want: []string{"go"},
+		},
+		{
+			name: "Trailing punctuation is not part of the tag",
+			body: "Ship it! #friday, #release.",
+			want: []string{"friday", "release"},
+		},
+		{
+			name: "Tags need a letter",
+			body: "We're #1 on #100days",
+			want: []string{"100days"},
+		},
+		{
+			name: "Tag glued to a word is ignored",
+			body: "email me at abc#def or #real",
+			want: []string{"real"},
+		},
+		{
+			name: "Back to back tags",
+			body: "#one#two #three",
+			want: []string{"one", "three"},
+		},
+		{
+			name: "Unicode tags",
+			body: "#Café #東京",
+			want: []string{"café", "東京"},
+		},
+		{
+			name: "Lone hash",
+			body: "# #",
+			want: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Extract(tc.body)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Extract(%q) = %q, want %q", tc.body, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{input: "#Chirpy", want: "chirpy", wantOK: true},
+		{input: "chirpy_dev", want: "chirpy_dev", wantOK: true},
+		{input: "123", wantOK: false},
+		{input: "two words", wantOK: false},
+		{input: "#", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, ok := Normalize(tc.input)
+			if ok != tc.wantOK {
+				t.Errorf("Normalize(%q) ok = %v, want %v", tc.input, ok, tc.wantOK)
+			}
+			if ok && got != tc.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFSStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error creating store: %v", err)
+	}
+
+	err = store.Put(ctx, "photo.jpg", strings.NewReader("pixels"))
+	if err != nil {
+		t.Fatalf("Error putting blob: %v", err)
+	}
+
+	rc, err := store.Open(ctx, "photo.jpg")
+	if err != nil {
+		t.Fatalf("Error opening blob: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "pixels" {
+		t.Errorf("Open() = %q, want %q", got, "pixels")
+	}
+
+	err = store.Delete(ctx, "photo.jpg")
+	if err != nil {
+		t.Fatalf("Error deleting blob: %v", err)
+	}
+
+	_, err = store.Open(ctx, "photo.jpg")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open() after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFSStoreRejectsPathTraversal(t *testing.T) {
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error creating store: %v", err)
+	}
+
+	for _, key := range []string{"../escape", "a/b", `a\b`, ".hidden", ""} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("Put(%q) expected an error", key)
+		}
+	}
+}
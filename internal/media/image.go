@@ -0,0 +1,108 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	MaxUploadSize     = 5 << 20
+	MaxImageDimension = 8192
+	ThumbnailSize     = 320
+)
+
+var ErrUnsupportedType = errors.New("only JPEG and PNG images are supported")
+
+type Image struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Data        []byte
+	Thumbnail   []byte
+}
+
+// ProcessImage validates an uploaded image and re-encodes it. Re-encoding
+// from decoded pixels drops EXIF and any other metadata the client sent.
+func ProcessImage(data []byte) (Image, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return Image{}, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, err
+	}
+	if config.Width > MaxImageDimension || config.Height > MaxImageDimension {
+		return Image{}, errors.New("image dimensions are too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, err
+	}
+
+	full, err := encode(img, contentType)
+	if err != nil {
+		return Image{}, err
+	}
+	thumb, err := encode(Thumbnail(img, ThumbnailSize), contentType)
+	if err != nil {
+		return Image{}, err
+	}
+
+	extension := ".jpg"
+	if contentType == "image/png" {
+		extension = ".png"
+	}
+
+	bounds := img.Bounds()
+	return Image{
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Data:        full,
+		Thumbnail:   thumb,
+	}, nil
+}
+
+// Thumbnail scales img so its longest side is at most maxSize, keeping the
+// aspect ratio. Images that are already small enough are returned as-is.
+func Thumbnail(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSize && h <= maxSize {
+		return img
+	}
+
+	tw, th := maxSize, h*maxSize/w
+	if h > w {
+		tw, th = w*maxSize/h, maxSize
+	}
+	tw, th = max(tw, 1), max(th, 1)
+
+	thumb := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			thumb.Set(x, y, img.At(bounds.Min.X+x*w/tw, bounds.Min.Y+y*h/th))
+		}
+	}
+	return thumb
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	}
+	return buf.Bytes(), err
+}
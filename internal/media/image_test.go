@@ -0,0 +1,95 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestProcessImageStripsEXIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(64, 48), nil); err != nil {
+		t.Fatalf("Error encoding test image: %v", err)
+	}
+
+	// Splice an APP1 EXIF segment in straight after the SOI marker.
+	exifPayload := []byte("Exif\x00\x00secret-gps-data")
+	segment := []byte{0xFF, 0xE1, 0x00, byte(len(exifPayload) + 2)}
+	segment = append(segment, exifPayload...)
+	data := append([]byte{}, buf.Bytes()[:2]...)
+	data = append(data, segment...)
+	data = append(data, buf.Bytes()[2:]...)
+
+	img, err := ProcessImage(data)
+	if err != nil {
+		t.Fatalf("Error processing image: %v", err)
+	}
+
+	if bytes.Contains(img.Data, []byte("secret-gps-data")) {
+		t.Error("Processed image still contains EXIF data")
+	}
+	if img.ContentType != "image/jpeg" || img.Extension != ".jpg" {
+		t.Errorf("Wrong type: got %s %s", img.ContentType, img.Extension)
+	}
+	if img.Width != 64 || img.Height != 48 {
+		t.Errorf("Wrong dimensions: got %dx%d, want 64x48", img.Width, img.Height)
+	}
+}
+
+func TestProcessImageRejectsUnsupportedTypes(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Plain text", data: []byte("definitely not an image")},
+		{name: "GIF", data: []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ProcessImage(tc.data); err == nil {
+				t.Error("ProcessImage() expected an error")
+			}
+		})
+	}
+}
+
+func TestProcessImagePNGThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(1000, 500)); err != nil {
+		t.Fatalf("Error encoding test image: %v", err)
+	}
+
+	img, err := ProcessImage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Error processing image: %v", err)
+	}
+
+	thumb, err := png.Decode(bytes.NewReader(img.Thumbnail))
+	if err != nil {
+		t.Fatalf("Error decoding thumbnail: %v", err)
+	}
+	if got := thumb.Bounds(); got.Dx() != ThumbnailSize || got.Dy() != ThumbnailSize/2 {
+		t.Errorf("Wrong thumbnail size: got %dx%d, want %dx%d", got.Dx(), got.Dy(), ThumbnailSize, ThumbnailSize/2)
+	}
+}
+
+func TestThumbnailKeepsSmallImages(t *testing.T) {
+	img := testImage(10, 20)
+	if got := Thumbnail(img, ThumbnailSize); got != img {
+		t.Error("Thumbnail() should return small images unchanged")
+	}
+}
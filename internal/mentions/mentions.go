@@ -0,0 +1,67 @@
+package mentions
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	MinHandleLength = 3
+	MaxHandleLength = 15
+)
+
+// NormalizeHandle lowercases a handle (with or without its leading @) and
+// checks it is 3-15 ASCII letters, digits or underscores.
+func NormalizeHandle(handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimPrefix(handle, "@"))
+
+	if len(handle) < MinHandleLength || len(handle) > MaxHandleLength {
+		return "", errors.New("handle must be between 3 and 15 characters")
+	}
+
+	for i := 0; i < len(handle); i++ {
+		if !isHandleByte(handle[i]) {
+			return "", errors.New("handle can only contain letters, numbers and underscores")
+		}
+	}
+
+	return handle, nil
+}
+
+// Extract returns the normalised, de-duplicated @handles mentioned in a chirp
+// body. An @ glued to the end of a word (like an email address) isn't a mention.
+func Extract(body string) []string {
+	handles := []string{}
+	seen := map[string]bool{}
+
+	for i := 0; i < len(body); i++ {
+		if body[i] != '@' || (i > 0 && isHandleByte(body[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(body) && isHandleByte(body[end]) {
+			end++
+		}
+
+		// @alice@example.com is an address, not a mention.
+		if end < len(body) && body[end] == '@' {
+			i = end - 1
+			continue
+		}
+
+		handle, err := NormalizeHandle(body[i+1 : end])
+		if err == nil && !seen[handle] {
+			seen[handle] = true
+			handles = append(handles, handle)
+		}
+
+		i = end - 1
+	}
+
+	return handles
+}
+
+func isHandleByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '_'
+}
@@ -0,0 +1,76 @@
+package mentions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "No mentions",
+			body: "hello world",
+			want: []string{},
+		},
+		{
+			name: "Mentions are lowercased and de-duplicated",
+			body: "@Alice and @bob_1, meet @ALICE",
+			want: []string{"alice", "bob_1"},
+		},
+		{
+			name: "Email addresses are not mentions",
+			body: "mail joe@example.com or @alice@example.com",
+			want: []string{},
+		},
+		{
+			name: "Trailing punctuation",
+			body: "thanks @carol!",
+			want: []string{"carol"},
+		},
+		{
+			name: "Handles that are too short or long are ignored",
+			body: "@ab @abcdefghijklmnopq @okay",
+			want: []string{"okay"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Extract(tc.body)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Extract(%q) = %q, want %q", tc.body, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeHandle(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "@Chirpy_Fan", want: "chirpy_fan"},
+		{input: "abc", want: "abc"},
+		{input: "ab", wantErr: true},
+		{input: "abcdefghijklmnop", wantErr: true},
+		{input: "bad-handle", wantErr: true},
+		{input: "émile", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := NormalizeHandle(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("NormalizeHandle(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("NormalizeHandle(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package moderation
+
+import (
+	"slices"
+)
+
+// Action is what happens to a chirp containing a word from the list.
+type Action string
+
+const (
+	// ActionBlock rejects the chirp.
+	ActionBlock Action = "block"
+	// ActionMask replaces the word with asterisks.
+	ActionMask Action = "mask"
+	// ActionFlag keeps the chirp as written but records it for review.
+	ActionFlag Action = "flag"
+)
+
+// ParseAction checks action is one of block, mask or flag.
+func ParseAction(action string) (Action, error) {
+	switch Action(action) {
+	case ActionBlock, ActionMask, ActionFlag:
+		return Action(action), nil
+	}
+	return "", errInvalidAction
+}
+
+// severity orders actions so the strictest wins when two rules collide.
+func (a Action) severity() int {
+	switch a {
+	case ActionBlock:
+		return 3
+	case ActionMask:
+		return 2
+	case ActionFlag:
+		return 1
+	}
+	return 0
+}
+
+// Verdict is the outcome of running text through a Filter.
+type Verdict struct {
+	// Text is the input with any masked words replaced.
+	Text string
+	// Blocked and Flagged list the normalised words that triggered each action.
+	Blocked []string
+	Flagged []string
+}
+
+func (v Verdict) IsBlocked() bool {
+	return len(v.Blocked) > 0
+}
+
+func (v Verdict) IsFlagged() bool {
+	return len(v.Flagged) > 0
+}
+
+// A Filter checks text and reports what should happen to it.
+type Filter interface {
+	Check(text string) Verdict
+}
+
+// Chain runs each filter in turn, passing the text as masked so far to the
+// next one and collecting every filter's blocked and flagged words.
+type Chain []Filter
+
+func (c Chain) Check(text string) Verdict {
+	verdict := Verdict{Text: text}
+	for _, filter := range c {
+		next := filter.Check(verdict.Text)
+		verdict.Text = next.Text
+		verdict.Blocked = appendUnique(verdict.Blocked, next.Blocked...)
+		verdict.Flagged = appendUnique(verdict.Flagged, next.Flagged...)
+	}
+	return verdict
+}
+
+func appendUnique(words []string, more ...string) []string {
+	for _, word := range more {
+		if !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,236 @@
+package moderation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+const mask = "****"
+
+// Fullwidth forms of the printable ASCII characters, as used in CJK text and
+// to dodge filters, sit at a fixed offset from their ASCII counterparts.
+const (
+	fullwidthFirst  = '\uFF01'
+	fullwidthLast   = '\uFF5E'
+	fullwidthOffset = fullwidthFirst - '!'
+)
+
+var (
+	errInvalidAction = errors.New("action must be block, mask or flag")
+	errInvalidWord   = errors.New("word must be a single word containing at least one letter")
+)
+
+// leetspeak maps the digits and symbols commonly swapped for letters back
+// to the letters they stand in for.
+var leetspeak = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'l',
+	'+': 't',
+}
+
+// Rule is a single entry in a word list.
+type Rule struct {
+	Word   string
+	Action Action
+}
+
+// WordList is a Filter that matches whole words against a list of rules.
+// Matching ignores case, punctuation and leetspeak, so "F0rn@x!" matches a
+// rule for "fornax". The rules can be swapped at any time with Replace.
+type WordList struct {
+	rules atomic.Pointer[map[string]Action]
+}
+
+func NewWordList(rules []Rule) (*WordList, error) {
+	list := &WordList{}
+	err := list.Replace(rules)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Replace swaps in a new set of rules. It is safe to call while other
+// goroutines are checking text. If a word appears more than once, the
+// strictest action wins.
+func (l *WordList) Replace(rules []Rule) error {
+	byWord := make(map[string]Action, len(rules))
+	for _, rule := range rules {
+		word, err := NormalizeWord(rule.Word)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Word, err)
+		}
+		action, err := ParseAction(string(rule.Action))
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Word, err)
+		}
+		if existing, ok := byWord[word]; !ok || action.severity() > existing.severity() {
+			byWord[word] = action
+		}
+	}
+
+	l.rules.Store(&byWord)
+	return nil
+}
+
+func (l *WordList) Check(text string) Verdict {
+	verdict := Verdict{Text: text}
+
+	rules := l.rules.Load()
+	if rules == nil || len(*rules) == 0 {
+		return verdict
+	}
+
+	var out strings.Builder
+	rest := text
+	for rest != "" {
+		start := strings.IndexFunc(rest, isWordRune)
+		if start < 0 {
+			out.WriteString(rest)
+			break
+		}
+		out.WriteString(rest[:start])
+		rest = rest[start:]
+
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		out.WriteString(checkToken(*rules, rest[:end], &verdict))
+		rest = rest[end:]
+	}
+
+	verdict.Text = out.String()
+	return verdict
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r)
+}
+
+// checkToken matches a whitespace-separated token against the rules and
+// returns it, masked if need be. The token is tried first without any
+// punctuation around it, so "fornax!" is masked as "****!", and then as a
+// whole, so symbols standing in for letters at either end still count.
+func checkToken(rules map[string]Action, token string, verdict *Verdict) string {
+	start := strings.IndexFunc(token, isAlphanumeric)
+	end := strings.LastIndexFunc(token, isAlphanumeric)
+
+	if start >= 0 {
+		_, size := utf8.DecodeRuneInString(token[end:])
+		end += size
+		if word := normalize(token[start:end]); word != "" {
+			if action, ok := rules[word]; ok {
+				return apply(action, word, token, start, end, verdict)
+			}
+		}
+	}
+
+	if word := normalize(token); word != "" {
+		if action, ok := rules[word]; ok {
+			return apply(action, word, token, 0, len(token), verdict)
+		}
+	}
+
+	return token
+}
+
+func apply(action Action, word, token string, start, end int, verdict *Verdict) string {
+	switch action {
+	case ActionBlock:
+		verdict.Blocked = appendUnique(verdict.Blocked, word)
+	case ActionFlag:
+		verdict.Flagged = appendUnique(verdict.Flagged, word)
+	case ActionMask:
+		return token[:start] + mask + token[end:]
+	}
+	return token
+}
+
+func isAlphanumeric(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// normalize lowercases a word, turns fullwidth forms and leetspeak back into
+// plain letters and drops everything else that isn't a letter, including
+// punctuation, combining accents and invisible characters.
+func normalize(word string) string {
+	var b strings.Builder
+	for _, r := range word {
+		if r >= fullwidthFirst && r <= fullwidthLast {
+			r -= fullwidthOffset
+		}
+		if letter, ok := leetspeak[r]; ok {
+			b.WriteRune(letter)
+			continue
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// NormalizeWord puts a word into the form rules are stored and matched in.
+func NormalizeWord(word string) (string, error) {
+	word = strings.TrimSpace(word)
+	if strings.IndexFunc(word, unicode.IsSpace) >= 0 {
+		return "", errInvalidWord
+	}
+	if strings.IndexFunc(word, unicode.IsLetter) < 0 {
+		return "", errInvalidWord
+	}
+	return normalize(word), nil
+}
+
+// ParseRules reads a word list with one rule per line: a word, optionally
+// followed by block, mask or flag. Words without an action are masked.
+// Blank lines and lines starting with # are ignored.
+func ParseRules(r io.Reader) ([]Rule, error) {
+	rules := []Rule{}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected a word and an optional action", lineNumber)
+		}
+
+		rule := Rule{Word: fields[0], Action: ActionMask}
+		if len(fields) == 2 {
+			action, err := ParseAction(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			rule.Action = action
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,207 @@
+package moderation
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWordListCheck(t *testing.T) {
+	list, err := NewWordList([]Rule{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "sharbert", Action: ActionMask},
+		{Word: "fornax", Action: ActionMask},
+		{Word: "blorp", Action: ActionBlock},
+		{Word: "snark", Action: ActionFlag},
+	})
+	if err != nil {
+		t.Fatalf("NewWordList() error = %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		text        string
+		wantText    string
+		wantBlocked []string
+		wantFlagged []string
+	}{
+		{
+			name:     "Clean text is unchanged",
+			text:     "I had something interesting for breakfast",
+			wantText: "I had something interesting for breakfast",
+		},
+		{
+			name:     "Matching ignores case",
+			text:     "This is a Kerfuffle opinion I need to share with the world",
+			wantText: "This is a **** opinion I need to share with the world",
+		},
+		{
+			name:     "Punctuation around a word is kept",
+			text:     "I hear Mastodon is better than Chirpy. sharbert, fornax!",
+			wantText: "I hear Mastodon is better than Chirpy. ****, ****!",
+		},
+		{
+			name:     "Punctuation inside a word is ignored",
+			text:     "what a f.o.r.n.a.x",
+			wantText: "what a ****",
+		},
+		{
+			name:     "Leetspeak",
+			text:     "sh4rb3rt and f0rn@x",
+			wantText: "**** and ****",
+		},
+		{
+			name:     "Symbol standing in for a leading letter",
+			text:     "$harbert",
+			wantText: "****",
+		},
+		{
+			name:     "Mentions keep their @",
+			text:     "hi @fornax",
+			wantText: "hi @****",
+		},
+		{
+			name:     "Invisible characters are ignored",
+			text:     "forn​ax",
+			wantText: "****",
+		},
+		{
+			name:     "Fullwidth letters",
+			text:     "ＦＯＲＮＡＸ",
+			wantText: "****",
+		},
+		{
+			name:     "Words inside other words don't match",
+			text:     "fornaxes kerfuffled",
+			wantText: "fornaxes kerfuffled",
+		},
+		{
+			name:     "Whitespace is preserved",
+			text:     "  fornax\n\tok  ",
+			wantText: "  ****\n\tok  ",
+		},
+		{
+			name:        "Blocked words",
+			text:        "blorp BL0RP",
+			wantText:    "blorp BL0RP",
+			wantBlocked: []string{"blorp"},
+		},
+		{
+			name:        "Flagged words",
+			text:        "such snark",
+			wantText:    "such snark",
+			wantFlagged: []string{"snark"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := list.Check(tc.text)
+			if got.Text != tc.wantText {
+				t.Errorf("Check(%q).Text = %q, want %q", tc.text, got.Text, tc.wantText)
+			}
+			if !slices.Equal(got.Blocked, tc.wantBlocked) {
+				t.Errorf("Check(%q).Blocked = %q, want %q", tc.text, got.Blocked, tc.wantBlocked)
+			}
+			if !slices.Equal(got.Flagged, tc.wantFlagged) {
+				t.Errorf("Check(%q).Flagged = %q, want %q", tc.text, got.Flagged, tc.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestWordListReplace(t *testing.T) {
+	list, err := NewWordList([]Rule{{Word: "fornax", Action: ActionMask}})
+	if err != nil {
+		t.Fatalf("NewWordList() error = %v", err)
+	}
+
+	err = list.Replace([]Rule{
+		{Word: "fornax", Action: ActionFlag},
+		{Word: "F0RNAX", Action: ActionBlock},
+	})
+	if err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+
+	got := list.Check("fornax")
+	if !got.IsBlocked() {
+		t.Errorf("Check() after Replace() = %+v, want the strictest rule to win", got)
+	}
+
+	err = list.Replace([]Rule{{Word: "fornax", Action: "shout"}})
+	if err == nil {
+		t.Fatal("Replace() with an invalid action should fail")
+	}
+	if got := list.Check("fornax"); !got.IsBlocked() {
+		t.Errorf("Check() after a failed Replace() = %+v, want the old rules kept", got)
+	}
+}
+
+func TestChain(t *testing.T) {
+	masks, _ := NewWordList([]Rule{{Word: "fornax", Action: ActionMask}})
+	flags, _ := NewWordList([]Rule{{Word: "snark", Action: ActionFlag}})
+
+	got := Chain{masks, flags}.Check("fornax snark")
+	if got.Text != "**** snark" {
+		t.Errorf("Chain.Check().Text = %q, want %q", got.Text, "**** snark")
+	}
+	if !slices.Equal(got.Flagged, []string{"snark"}) {
+		t.Errorf("Chain.Check().Flagged = %q, want %q", got.Flagged, []string{"snark"})
+	}
+}
+
+func TestNormalizeWord(t *testing.T) {
+	testCases := []struct {
+		name    string
+		word    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Lowercased", word: "Fornax", want: "fornax"},
+		{name: "Leetspeak", word: "f0rn4x", want: "fornax"},
+		{name: "Trimmed", word: " fornax ", want: "fornax"},
+		{name: "Two words", word: "for nax", wantErr: true},
+		{name: "No letters", word: "1234", wantErr: true},
+		{name: "Empty", word: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeWord(tc.word)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NormalizeWord(%q) error = %v, wantErr %v", tc.word, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizeWord(%q) = %q, want %q", tc.word, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := `# default words
+kerfuffle
+fornax   block
+
+snark flag
+`
+	got, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	want := []Rule{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "fornax", Action: ActionBlock},
+		{Word: "snark", Action: ActionFlag},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseRules() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"fornax shout", "fornax mask please"} {
+		if _, err := ParseRules(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseRules(%q) should fail", bad)
+		}
+	}
+}
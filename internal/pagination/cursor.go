@@ -0,0 +1,107 @@
+package pagination
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Params holds the page size and keyset position in the nullable form the
+// sqlc keyset queries expect.
+type Params struct {
+	Limit           int32
+	CursorCreatedAt sql.NullTime
+	CursorID        uuid.NullUUID
+}
+
+func ParseParams(query url.Values) (Params, error) {
+	limit, err := ParseLimit(query.Get("limit"))
+	if err != nil {
+		return Params{}, err
+	}
+
+	params := Params{Limit: limit}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := DecodeCursor(cursorStr)
+		if err != nil {
+			return Params{}, err
+		}
+		params.CursorCreatedAt = sql.NullTime{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+	}
+
+	return params, nil
+}
+
+// Trim cuts items fetched with Limit+1 back down to the page size and returns
+// the cursor for the next page, or "" when this is the last page.
+func Trim[T any](items []T, limit int32, key func(T) Cursor) ([]T, string) {
+	if len(items) <= int(limit) {
+		return items, ""
+	}
+	items = items[:limit]
+	return items, EncodeCursor(key(items[len(items)-1]))
+}
+
+func EncodeCursor(c Cursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	createdAtStr, idStr, found := strings.Cut(string(raw), "|")
+	if !found {
+		return Cursor{}, errors.New("malformed cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor id: %w", err)
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+func ParseLimit(s string) (int32, error) {
+	if s == "" {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid limit: %w", err)
+	}
+	if limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+	}
+
+	return int32(limit), nil
+}
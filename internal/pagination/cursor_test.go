@@ -0,0 +1,95 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := Cursor{
+		CreatedAt: time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("Error decoding cursor: %v", err)
+	}
+
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("Wrong CreatedAt in cursor. got %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+	if got.ID != want.ID {
+		t.Errorf("Wrong ID in cursor. got %v, want %v", got.ID, want.ID)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Not base64", cursor: "!!!"},
+		{name: "Missing separator", cursor: "bm9zZXBhcmF0b3I"},
+		{name: "Bad timestamp", cursor: "eWVzdGVyZGF5fDEyMw"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tc.cursor); err == nil {
+				t.Errorf("DecodeCursor(%q) expected an error", tc.cursor)
+			}
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    int32
+		wantErr bool
+	}{
+		{name: "Empty uses default", input: "", want: DefaultLimit},
+		{name: "Valid limit", input: "50", want: 50},
+		{name: "Zero", input: "0", wantErr: true},
+		{name: "Above max", input: "101", wantErr: true},
+		{name: "Not a number", input: "ten", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseLimit(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseLimit() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("ParseLimit() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	key := func(id uuid.UUID) Cursor { return Cursor{ID: id} }
+
+	page, next := Trim(ids, 3, key)
+	if len(page) != 3 || next != "" {
+		t.Errorf("Trim() on last page = %d items, cursor %q; want 3 items and no cursor", len(page), next)
+	}
+
+	page, next = Trim(ids, 2, key)
+	if len(page) != 2 {
+		t.Fatalf("Trim() returned %d items, want 2", len(page))
+	}
+	cursor, err := DecodeCursor(next)
+	if err != nil {
+		t.Fatalf("Error decoding next cursor: %v", err)
+	}
+	if cursor.ID != ids[1] {
+		t.Errorf("Next cursor points at %v, want %v", cursor.ID, ids[1])
+	}
+}
@@ -0,0 +1,59 @@
+package polls
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MinOptions      = 2
+	MaxOptions      = 4
+	MaxOptionLength = 25
+	MinDuration     = 5 * time.Minute
+	MaxDuration     = 7 * 24 * time.Hour
+)
+
+// NormalizeOptions trims each option and checks there are 2-4 of them, each
+// non-empty, at most 25 characters and distinct regardless of case.
+func NormalizeOptions(options []string) ([]string, error) {
+	if len(options) < MinOptions || len(options) > MaxOptions {
+		return nil, fmt.Errorf("a poll needs between %d and %d options", MinOptions, MaxOptions)
+	}
+
+	normalized := make([]string, 0, len(options))
+	seen := map[string]bool{}
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, errors.New("poll options can't be empty")
+		}
+		if utf8.RuneCountInString(option) > MaxOptionLength {
+			return nil, fmt.Errorf("poll options can be at most %d characters", MaxOptionLength)
+		}
+
+		key := strings.ToLower(option)
+		if seen[key] {
+			return nil, errors.New("poll options must be different")
+		}
+		seen[key] = true
+		normalized = append(normalized, option)
+	}
+
+	return normalized, nil
+}
+
+// ValidateDuration checks a poll opening at opensAt and closing at closesAt
+// runs for between five minutes and seven days.
+func ValidateDuration(opensAt, closesAt time.Time) error {
+	duration := closesAt.Sub(opensAt)
+	if duration < MinDuration {
+		return errors.New("a poll must stay open for at least 5 minutes")
+	}
+	if duration > MaxDuration {
+		return errors.New("a poll can stay open for at most 7 days")
+	}
+	return nil
+}
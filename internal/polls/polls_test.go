@@ -0,0 +1,94 @@
+package polls
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNormalizeOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "Options are trimmed",
+			options: []string{" Tea ", "Coffee"},
+			want:    []string{"Tea", "Coffee"},
+		},
+		{
+			name:    "Four options",
+			options: []string{"a", "b", "c", "d"},
+			want:    []string{"a", "b", "c", "d"},
+		},
+		{
+			name:    "Too few options",
+			options: []string{"Tea"},
+			wantErr: true,
+		},
+		{
+			name:    "Too many options",
+			options: []string{"a", "b", "c", "d", "e"},
+			wantErr: true,
+		},
+		{
+			name:    "Blank option",
+			options: []string{"Tea", "   "},
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate options ignore case",
+			options: []string{"Tea", "tea"},
+			wantErr: true,
+		},
+		{
+			name:    "Length counts characters not bytes",
+			options: []string{"ééééééééééééééééééééééééé", "no"},
+			want:    []string{"ééééééééééééééééééééééééé", "no"},
+		},
+		{
+			name:    "Option too long",
+			options: []string{"abcdefghijklmnopqrstuvwxyz", "no"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeOptions(tc.options)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NormalizeOptions(%q) error = %v, wantErr %v", tc.options, err, tc.wantErr)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("NormalizeOptions(%q) = %q, want %q", tc.options, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	opensAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		duration time.Duration
+		wantErr  bool
+	}{
+		{name: "Shortest poll", duration: MinDuration},
+		{name: "Longest poll", duration: MaxDuration},
+		{name: "Too short", duration: MinDuration - time.Second, wantErr: true},
+		{name: "Too long", duration: MaxDuration + time.Second, wantErr: true},
+		{name: "Closes before it opens", duration: -time.Hour, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDuration(opensAt, opensAt.Add(tc.duration))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateDuration() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+	MaxAvatarURLLength   = 2048
+)
+
+// NormalizeDisplayName trims a display name and checks it is at most 50
+// characters on a single line. An empty name clears it.
+func NormalizeDisplayName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if !utf8.ValidString(name) {
+		return "", errors.New("display name must be valid UTF-8")
+	}
+	if utf8.RuneCountInString(name) > MaxDisplayNameLength {
+		return "", fmt.Errorf("display name can be at most %d characters", MaxDisplayNameLength)
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", errors.New("display name can't contain line breaks or control characters")
+	}
+
+	return name, nil
+}
+
+// NormalizeBio trims a bio and checks it is at most 160 characters. Line
+// breaks are allowed; other control characters aren't.
+func NormalizeBio(bio string) (string, error) {
+	bio = strings.TrimSpace(strings.ReplaceAll(bio, "\r\n", "\n"))
+
+	if !utf8.ValidString(bio) {
+		return "", errors.New("bio must be valid UTF-8")
+	}
+	if utf8.RuneCountInString(bio) > MaxBioLength {
+		return "", fmt.Errorf("bio can be at most %d characters", MaxBioLength)
+	}
+	if strings.IndexFunc(bio, func(r rune) bool { return r != '\n' && unicode.IsControl(r) }) >= 0 {
+		return "", errors.New("bio can't contain control characters")
+	}
+
+	return bio, nil
+}
+
+// NormalizeAvatarURL trims an avatar URL and checks it is an absolute http or
+// https URL. An empty URL clears the avatar.
+func NormalizeAvatarURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", nil
+	}
+
+	if len(rawURL) > MaxAvatarURLLength {
+		return "", fmt.Errorf("avatar URL can be at most %d characters", MaxAvatarURLLength)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("avatar URL isn't a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.New("avatar URL must use http or https")
+	}
+	if u.Host == "" {
+		return "", errors.New("avatar URL must include a host")
+	}
+
+	return u.String(), nil
+}
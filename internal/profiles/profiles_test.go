@@ -0,0 +1,91 @@
+package profiles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDisplayName(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Name is trimmed", input: "  Joe Vinten ", want: "Joe Vinten"},
+		{name: "Empty name clears it", input: "", want: ""},
+		{name: "Longest name", input: strings.Repeat("a", MaxDisplayNameLength), want: strings.Repeat("a", MaxDisplayNameLength)},
+		{name: "Name too long", input: strings.Repeat("a", MaxDisplayNameLength+1), wantErr: true},
+		{name: "Length counts characters not bytes", input: strings.Repeat("é", MaxDisplayNameLength), want: strings.Repeat("é", MaxDisplayNameLength)},
+		{name: "Line break", input: "Joe\nVinten", wantErr: true},
+		{name: "Invalid UTF-8", input: "Joe\xff", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeDisplayName(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NormalizeDisplayName(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizeDisplayName(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBio(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Bio is trimmed", input: " Writes Go.\n", want: "Writes Go."},
+		{name: "Line breaks are kept", input: "Writes Go.\r\nDrinks tea.", want: "Writes Go.\nDrinks tea."},
+		{name: "Longest bio", input: strings.Repeat("a", MaxBioLength), want: strings.Repeat("a", MaxBioLength)},
+		{name: "Bio too long", input: strings.Repeat("a", MaxBioLength+1), wantErr: true},
+		{name: "Control character", input: "Writes\x00Go", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeBio(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NormalizeBio(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizeBio(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeAvatarURL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "HTTPS URL", input: " https://example.com/me.png ", want: "https://example.com/me.png"},
+		{name: "HTTP URL", input: "http://example.com/me.png", want: "http://example.com/me.png"},
+		{name: "Empty URL clears it", input: "", want: ""},
+		{name: "Relative URL", input: "/media/me.png", wantErr: true},
+		{name: "Other scheme", input: "javascript:alert(1)", wantErr: true},
+		{name: "Missing host", input: "https:///me.png", wantErr: true},
+		{name: "URL too long", input: "https://example.com/" + strings.Repeat("a", MaxAvatarURLLength), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeAvatarURL(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NormalizeAvatarURL(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("NormalizeAvatarURL(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// BuildQuery turns user search input into a Postgres to_tsquery expression.
+// Quoted text becomes a phrase match, a trailing * becomes a prefix match,
+// and everything else is ANDed together.
+func BuildQuery(input string) (string, error) {
+	terms := []string{}
+
+	for i, part := range strings.Split(input, `"`) {
+		// Odd indexes sit between a pair of quotes.
+		if i%2 == 1 {
+			words := lexemes(part)
+			if len(words) > 0 {
+				terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(part) {
+			isPrefix := strings.HasSuffix(field, "*")
+			for _, word := range lexemes(field) {
+				if isPrefix {
+					word += ":*"
+				}
+				terms = append(terms, word)
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return "", errors.New("search query has no searchable terms")
+	}
+
+	return strings.Join(terms, " & "), nil
+}
+
+// lexemes strips everything that isn't a letter or digit so user input can
+// never inject tsquery operators.
+func lexemes(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,60 @@
+package search
+
+import "testing"
+
+func TestBuildQuery(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Single word",
+			input: "chirpy",
+			want:  "chirpy",
+		},
+		{
+			name:  "Multiple words are ANDed",
+			input: "hello   World",
+			want:  "hello & world",
+		},
+		{
+			name:  "Phrase query",
+			input: `"good morning" all`,
+			want:  "(good <-> morning) & all",
+		},
+		{
+			name:  "Prefix match",
+			input: "chir*",
+			want:  "chir:*",
+		},
+		{
+			name:  "Operators are stripped",
+			input: "cats & !dogs | (birds)",
+			want:  "cats & dogs & birds",
+		},
+		{
+			name:    "Only punctuation",
+			input:   `"" & !`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := BuildQuery(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("BuildQuery() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("BuildQuery() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,27 +1,40 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"sync/atomic"
 	"time"
 
+	"github.com/JoeVinten/chirpy/internal/auth"
 	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/media"
+	"github.com/JoeVinten/chirpy/internal/moderation"
+	"github.com/JoeVinten/chirpy/internal/pagination"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type apiConfig struct {
-	fileserverHits atomic.Int32
-	db             *database.Queries
-	platform       string
-	jwtSecret      string
-	polkaKey       string
+	fileserverHits    atomic.Int32
+	db                *database.Queries
+	dbConn            *sql.DB
+	blobStore         media.BlobStore
+	platform          string
+	jwtSecret         string
+	polkaKey          string
+	wordList          *moderation.WordList
+	wordListFile      string
+	moderator         moderation.Filter
+	maxChirpLengthRed int
 }
 
 type User struct {
@@ -32,14 +45,66 @@ type User struct {
 	Token        string    `json:"token"`
 	RefreshToken string    `json:"refresh_token"`
 	IsChirpyRed  bool      `json:"is_chirpy_red"`
+	Handle       string    `json:"handle,omitempty"`
+	DisplayName  string    `json:"display_name"`
+	Bio          string    `json:"bio"`
+	AvatarURL    string    `json:"avatar_url"`
+	Role         string    `json:"role"`
 }
 
 type Chirp struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Body      string    `json:"body"`
-	UserID    uuid.UUID `json:"user_id"`
+	ID           uuid.UUID     `json:"id"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Body         string        `json:"body"`
+	UserID       uuid.UUID     `json:"user_id"`
+	Visibility   string        `json:"visibility"`
+	InReplyTo    uuid.NullUUID `json:"in_reply_to"`
+	ReplyCount   int32         `json:"reply_count"`
+	LikeCount    int32         `json:"like_count"`
+	LikedByMe    bool          `json:"liked_by_me"`
+	RechirpOf    *Chirp        `json:"rechirp_of,omitempty"`
+	QuoteOf      *Chirp        `json:"quote_of,omitempty"`
+	RechirpCount int32         `json:"rechirp_count"`
+	QuoteCount   int32         `json:"quote_count"`
+	Edited       bool          `json:"edited"`
+	Mentions     []Mention     `json:"mentions"`
+	Media        []Media       `json:"media"`
+	Poll         *Poll         `json:"poll,omitempty"`
+	Pinned       bool          `json:"pinned"`
+	PublishAt    *time.Time    `json:"publish_at,omitempty"`
+
+	rechirpOfID uuid.NullUUID
+	quoteOfID   uuid.NullUUID
+}
+
+func chirpFromDB(chirp database.Chirp) Chirp {
+	c := Chirp{
+		ID:           chirp.ID,
+		CreatedAt:    chirp.CreatedAt,
+		UpdatedAt:    chirp.UpdatedAt,
+		Body:         chirp.Body,
+		UserID:       chirp.UserID,
+		Visibility:   chirp.Visibility,
+		InReplyTo:    chirp.InReplyTo,
+		ReplyCount:   chirp.ReplyCount,
+		LikeCount:    chirp.LikeCount,
+		RechirpCount: chirp.RechirpCount,
+		QuoteCount:   chirp.QuoteCount,
+		Edited:       chirp.EditedAt.Valid,
+		Mentions:     []Mention{},
+		Media:        []Media{},
+		rechirpOfID:  chirp.RechirpOf,
+		quoteOfID:    chirp.QuoteOf,
+	}
+	if chirp.ScheduledFor.Valid {
+		c.PublishAt = &chirp.ScheduledFor.Time
+	}
+	return c
+}
+
+func chirpKey(chirp database.Chirp) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: chirp.CreatedAt, ID: chirp.ID}
 }
 
 func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
@@ -78,6 +143,11 @@ func respondWithError(w http.ResponseWriter, code int, msg string, err error) {
 
 }
 
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	dat, err := json.Marshal(payload)
@@ -102,21 +172,69 @@ func main() {
 		log.Fatalf("Error contecting to the db: %s", err)
 	}
 	dbQueries := database.New(db)
+
+	if len(os.Args) > 1 {
+		err := runCommand(context.Background(), db, os.Args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+	blobStore, err := media.NewFSStore(mediaDir)
+	if err != nil {
+		log.Fatalf("Error creating media store: %s", err)
+	}
+
+	maxChirpLengthRed := defaultMaxChirpLengthRed
+	if maxLength := os.Getenv("CHIRPY_RED_MAX_CHIRP_LENGTH"); maxLength != "" {
+		maxChirpLengthRed, err = strconv.Atoi(maxLength)
+		if err != nil || maxChirpLengthRed < maxChirpLength {
+			log.Fatalf("CHIRPY_RED_MAX_CHIRP_LENGTH must be a number of at least %d", maxChirpLength)
+		}
+	}
+
 	apiCfg := &apiConfig{
-		fileserverHits: atomic.Int32{},
-		db:             dbQueries,
-		platform:       os.Getenv("PLATFORM"),
-		jwtSecret:      os.Getenv("JWT_SECRET"),
-		polkaKey:       os.Getenv("POLKA_KEY"),
+		fileserverHits:    atomic.Int32{},
+		db:                dbQueries,
+		dbConn:            db,
+		blobStore:         blobStore,
+		platform:          os.Getenv("PLATFORM"),
+		jwtSecret:         os.Getenv("JWT_SECRET"),
+		polkaKey:          os.Getenv("POLKA_KEY"),
+		wordList:          &moderation.WordList{},
+		wordListFile:      os.Getenv("MODERATION_WORDS_FILE"),
+		maxChirpLengthRed: maxChirpLengthRed,
+	}
+	apiCfg.moderator = moderation.Chain{apiCfg.wordList}
+
+	err = apiCfg.reloadWordList(context.Background())
+	if err != nil {
+		log.Fatalf("Error loading moderation word list: %s", err)
 	}
 
 	const port = "8080"
 	mux := http.NewServeMux()
 
 	mux.Handle("/app/", http.StripPrefix("/app/", apiCfg.middlewareMetricsInc(http.FileServer(http.Dir(".")))))
+	mux.HandleFunc("GET /media/{key}", apiCfg.middlewareOptionalAuth(apiCfg.handlerServeMedia))
 
-	mux.HandleFunc("GET /admin/metrics", apiCfg.writeRequests)
-	mux.HandleFunc("POST /admin/reset", apiCfg.handlerReset)
+	mux.HandleFunc("GET /admin/metrics", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.writeRequests))
+	mux.HandleFunc("POST /admin/reset", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.handlerReset))
+	mux.HandleFunc("GET /admin/moderation/rules", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerGetModerationRules))
+	mux.HandleFunc("PUT /admin/moderation/rules/{word}", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerPutModerationRule))
+	mux.HandleFunc("DELETE /admin/moderation/rules/{word}", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerDeleteModerationRule))
+	mux.HandleFunc("GET /admin/moderation/flags", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerGetModerationFlags))
+	mux.HandleFunc("GET /admin/reports", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerGetReports))
+	mux.HandleFunc("POST /admin/reports/{reportID}/resolve", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerResolveReport))
+	mux.HandleFunc("GET /admin/audit", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.handlerGetAuditLog))
+	mux.HandleFunc("PUT /admin/users/{userID}/role", apiCfg.middlewareRequireRole(auth.RoleAdmin, apiCfg.handlerSetUserRole))
+	mux.HandleFunc("POST /admin/users/{userID}/suspend", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerSuspendUser))
+	mux.HandleFunc("POST /admin/users/{userID}/unsuspend", apiCfg.middlewareRequireRole(auth.RoleModerator, apiCfg.handlerUnsuspendUser))
 	mux.HandleFunc("GET /api/healthz", func(w http.ResponseWriter, r *http.Request) {
 		r.Header.Add("Content-Type", "text/plain;charset=utf-8")
 		w.WriteHeader(200)
@@ -126,23 +244,76 @@ func main() {
 	mux.HandleFunc("POST /api/users", apiCfg.handlerCreateUser)
 	mux.HandleFunc("POST /api/login", apiCfg.handlerLogin)
 	mux.HandleFunc("POST /api/chirps", apiCfg.middlewareAuth(apiCfg.handlerCreateChirp))
+	mux.HandleFunc("POST /api/media", apiCfg.middlewareAuth(apiCfg.handlerUploadMedia))
 	mux.HandleFunc("POST /api/refresh", apiCfg.handlerRefreshToken)
 	mux.HandleFunc("POST /api/revoke", apiCfg.handlerRevokeToken)
 
-	mux.HandleFunc("GET /api/chirps", apiCfg.handlerGetChirps)
-	mux.HandleFunc("GET /api/chirps/{chirpID}", apiCfg.handlerGetChirp)
+	mux.HandleFunc("GET /api/chirps", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetChirps))
+	mux.HandleFunc("GET /api/chirps/search", apiCfg.middlewareOptionalAuth(apiCfg.handlerSearchChirps))
+	mux.HandleFunc("GET /api/chirps/scheduled", apiCfg.middlewareAuth(apiCfg.handlerGetScheduledChirps))
+	mux.HandleFunc("GET /api/chirps/{chirpID}", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetChirp))
+	mux.HandleFunc("GET /api/chirps/{chirpID}/thread", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetThread))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/likes", apiCfg.middlewareAuth(apiCfg.handlerLikeChirp))
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/likes", apiCfg.middlewareAuth(apiCfg.handlerUnlikeChirp))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/bookmark", apiCfg.middlewareAuth(apiCfg.handlerBookmarkChirp))
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/bookmark", apiCfg.middlewareAuth(apiCfg.handlerRemoveBookmark))
+	mux.HandleFunc("GET /api/bookmarks", apiCfg.middlewareAuth(apiCfg.handlerGetBookmarks))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/pin", apiCfg.middlewareAuth(apiCfg.handlerPinChirp))
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/pin", apiCfg.middlewareAuth(apiCfg.handlerUnpinChirp))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/poll/votes", apiCfg.middlewareAuth(apiCfg.handlerVotePoll))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/reports", apiCfg.middlewareAuth(apiCfg.handlerReportChirp))
+
+	mux.HandleFunc("POST /api/drafts", apiCfg.middlewareAuth(apiCfg.handlerCreateDraft))
+	mux.HandleFunc("GET /api/drafts", apiCfg.middlewareAuth(apiCfg.handlerGetDrafts))
+	mux.HandleFunc("GET /api/drafts/{draftID}", apiCfg.middlewareAuth(apiCfg.handlerGetDraft))
+	mux.HandleFunc("PUT /api/drafts/{draftID}", apiCfg.middlewareAuth(apiCfg.handlerUpdateDraft))
+	mux.HandleFunc("DELETE /api/drafts/{draftID}", apiCfg.middlewareAuth(apiCfg.handlerDeleteDraft))
+	mux.HandleFunc("POST /api/drafts/{draftID}/publish", apiCfg.middlewareAuth(apiCfg.handlerPublishDraft))
+
+	mux.HandleFunc("GET /api/hashtags/trending", apiCfg.handlerGetTrendingHashtags)
+	mux.HandleFunc("GET /api/hashtags/{tag}/chirps", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetHashtagChirps))
+
+	mux.HandleFunc("GET /api/notifications", apiCfg.middlewareAuth(apiCfg.handlerGetNotifications))
+	mux.HandleFunc("POST /api/notifications/read", apiCfg.middlewareAuth(apiCfg.handlerMarkNotificationsRead))
 
 	mux.HandleFunc("PUT /api/users", apiCfg.middlewareAuth(apiCfg.handlerUpdateAccount))
 
+	mux.HandleFunc("GET /api/users/{userID}", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetUserProfile))
+	mux.HandleFunc("GET /api/handles/{handle}", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetUserProfileByHandle))
+	mux.HandleFunc("POST /api/users/{userID}/follow", apiCfg.middlewareAuth(apiCfg.handlerFollowUser))
+	mux.HandleFunc("DELETE /api/users/{userID}/follow", apiCfg.middlewareAuth(apiCfg.handlerUnfollowUser))
+	mux.HandleFunc("GET /api/users/{userID}/followers", apiCfg.handlerGetFollowers)
+	mux.HandleFunc("GET /api/users/{userID}/following", apiCfg.handlerGetFollowing)
+	mux.HandleFunc("GET /api/users/{userID}/likes", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetUserLikes))
+	mux.HandleFunc("POST /api/users/{userID}/reports", apiCfg.middlewareAuth(apiCfg.handlerReportUser))
+	mux.HandleFunc("POST /api/users/{userID}/block", apiCfg.middlewareAuth(apiCfg.handlerBlockUser))
+	mux.HandleFunc("DELETE /api/users/{userID}/block", apiCfg.middlewareAuth(apiCfg.handlerUnblockUser))
+	mux.HandleFunc("POST /api/users/{userID}/mute", apiCfg.middlewareAuth(apiCfg.handlerMuteUser))
+	mux.HandleFunc("DELETE /api/users/{userID}/mute", apiCfg.middlewareAuth(apiCfg.handlerUnmuteUser))
+	mux.HandleFunc("GET /api/blocks", apiCfg.middlewareAuth(apiCfg.handlerGetBlocks))
+	mux.HandleFunc("GET /api/mutes", apiCfg.middlewareAuth(apiCfg.handlerGetMutes))
+
+	mux.HandleFunc("POST /api/filters", apiCfg.middlewareAuth(apiCfg.handlerCreateMuteFilter))
+	mux.HandleFunc("GET /api/filters", apiCfg.middlewareAuth(apiCfg.handlerGetMuteFilters))
+	mux.HandleFunc("DELETE /api/filters/{filterID}", apiCfg.middlewareAuth(apiCfg.handlerDeleteMuteFilter))
+	mux.HandleFunc("GET /api/timeline", apiCfg.middlewareAuth(apiCfg.handlerGetTimeline))
+
+	mux.HandleFunc("PUT /api/chirps/{chirpID}", apiCfg.middlewareAuth(apiCfg.handlerUpdateChirp))
+	mux.HandleFunc("GET /api/chirps/{chirpID}/history", apiCfg.middlewareOptionalAuth(apiCfg.handlerGetChirpHistory))
 	mux.HandleFunc("DELETE /api/chirps/{chirpID}", apiCfg.middlewareAuth(apiCfg.handlerDeleteChirp))
+	mux.HandleFunc("PUT /api/chirps/{chirpID}/schedule", apiCfg.middlewareAuth(apiCfg.handlerRescheduleChirp))
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/schedule", apiCfg.middlewareAuth(apiCfg.handlerCancelScheduledChirp))
 
 	mux.HandleFunc("POST /api/polka/webhooks", apiCfg.handlerUpgradeUser)
 
 	s := &http.Server{
 		Addr:    ":" + port,
-		Handler: mux,
+		Handler: middlewareRequestID(mux),
 	}
 
+	go apiCfg.runScheduler(context.Background(), schedulerInterval)
+	go apiCfg.runWordListReloader(context.Background(), moderationReloadInterval)
+
 	log.Printf("Serving on port: %s\n", port)
 
 	log.Fatal(s.ListenAndServe())
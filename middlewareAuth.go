@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/JoeVinten/chirpy/internal/auth"
@@ -10,7 +12,10 @@ import (
 
 type contextKey string
 
-const userIDKey contextKey = "userID"
+const (
+	userIDKey contextKey = "userID"
+	roleKey   contextKey = "role"
+)
 
 func (cfg *apiConfig) middlewareAuth(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -20,18 +25,101 @@ func (cfg *apiConfig) middlewareAuth(handler http.HandlerFunc) http.HandlerFunc
 			return
 		}
 
-		userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+		userID, role, err := auth.ValidateJWT(token, cfg.jwtSecret)
 		if err != nil {
 			respondWithError(w, http.StatusUnauthorized, "Invalid token ", err)
 			return
 		}
 
+		// Checked on every request so a suspension takes effect before the
+		// user's access token expires.
+		suspended, err := cfg.isSuspended(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check account status", err)
+			return
+		}
+		if suspended {
+			respondWithError(w, http.StatusForbidden, "Account suspended", nil)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		ctx = context.WithValue(ctx, roleKey, role)
 		handler(w, r.WithContext(ctx))
 	}
 }
 
+// middlewareOptionalAuth adds the caller's user ID to the context when the
+// request carries a valid JWT from a user who isn't suspended, and otherwise
+// lets the request through anonymously.
+func (cfg *apiConfig) middlewareOptionalAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			handler(w, r)
+			return
+		}
+
+		userID, role, err := auth.ValidateJWT(token, cfg.jwtSecret)
+		if err != nil {
+			handler(w, r)
+			return
+		}
+
+		suspended, err := cfg.isSuspended(r.Context(), userID)
+		if err != nil || suspended {
+			handler(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		ctx = context.WithValue(ctx, roleKey, role)
+		handler(w, r.WithContext(ctx))
+	}
+}
+
+// middlewareRequireRole authenticates the request like middlewareAuth and
+// only lets it through if the caller's role includes role. The role in the
+// token may be out of date, so the caller's current role is loaded instead
+// and replaces it in the context.
+func (cfg *apiConfig) middlewareRequireRole(role auth.Role, handler http.HandlerFunc) http.HandlerFunc {
+	return cfg.middlewareAuth(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := getUserID(r.Context())
+		current, err := cfg.db.GetUserRole(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusForbidden, "You don't have permission to do that", err)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check role", err)
+			return
+		}
+
+		currentRole := auth.Role(current)
+		if !currentRole.Includes(role) {
+			respondWithError(w, http.StatusForbidden, "You don't have permission to do that", nil)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), roleKey, currentRole)
+		handler(w, r.WithContext(ctx))
+	})
+}
+
 func getUserID(ctx context.Context) (uuid.UUID, bool) {
 	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
 	return userID, ok
 }
+
+// getRole returns the caller's role, or no role for anonymous requests.
+func getRole(ctx context.Context) auth.Role {
+	role, _ := ctx.Value(roleKey).(auth.Role)
+	return role
+}
+
+// getViewerID returns the caller's user ID in the nullable form the
+// visibility-aware queries expect. It's invalid for anonymous requests.
+func getViewerID(ctx context.Context) uuid.NullUUID {
+	userID, ok := getUserID(ctx)
+	return uuid.NullUUID{UUID: userID, Valid: ok}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDKey       contextKey = "requestID"
+	clientRequestIDKey contextKey = "clientRequestID"
+	requestIDHeader               = "X-Request-ID"
+	maxRequestIDLength            = 128
+)
+
+// middlewareRequestID tags every request with an ID we generate, and echoes
+// it in the response. Callers can't choose the ID, since it's what ties audit
+// log entries to a request. An ID sent by a proxy in front of us is kept
+// alongside it as the client request ID, if it's well formed.
+func middlewareRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := uuid.NewString()
+		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+
+		clientRequestID := r.Header.Get(requestIDHeader)
+		if validClientRequestID(clientRequestID) {
+			ctx = context.WithValue(ctx, clientRequestIDKey, clientRequestID)
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validClientRequestID reports whether id is 1-128 ASCII letters, digits,
+// dots, dashes or underscores.
+func validClientRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func getRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func getClientRequestID(ctx context.Context) string {
+	clientRequestID, _ := ctx.Value(clientRequestIDKey).(string)
+	return clientRequestID
+}
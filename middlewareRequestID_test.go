@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMiddlewareRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		header     string
+		wantClient string
+	}{
+		{name: "No header", header: "", wantClient: ""},
+		{name: "Proxy ID", header: "edge-7f3a.01_b", wantClient: "edge-7f3a.01_b"},
+		{name: "Spaces", header: "abc def", wantClient: ""},
+		{name: "Control characters", header: "abc\x01def", wantClient: ""},
+		{name: "Too long", header: strings.Repeat("a", maxRequestIDLength+1), wantClient: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestID, clientRequestID string
+			handler := middlewareRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestID = getRequestID(r.Context())
+				clientRequestID = getClientRequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.header != "" {
+				req.Header.Set(requestIDHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if _, err := uuid.Parse(requestID); err != nil {
+				t.Errorf("request ID %q isn't one we generated", requestID)
+			}
+			if got := rec.Header().Get(requestIDHeader); got != requestID {
+				t.Errorf("response %s = %q, want %q", requestIDHeader, got, requestID)
+			}
+			if clientRequestID != tc.wantClient {
+				t.Errorf("client request ID = %q, want %q", clientRequestID, tc.wantClient)
+			}
+		})
+	}
+}
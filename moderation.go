@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/moderation"
+)
+
+const moderationReloadInterval = 30 * time.Second
+
+// reloadWordList rebuilds the moderation word list from the word list file,
+// if one is configured, and the rules admins manage in the database.
+func (cfg *apiConfig) reloadWordList(ctx context.Context) error {
+	rules := []moderation.Rule{}
+
+	if cfg.wordListFile != "" {
+		f, err := os.Open(cfg.wordListFile)
+		if err != nil {
+			return err
+		}
+		fileRules, err := moderation.ParseRules(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	dbRules, err := cfg.db.GetModerationRules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, rule := range dbRules {
+		rules = append(rules, moderation.Rule{
+			Word:   rule.Word,
+			Action: moderation.Action(rule.Action),
+		})
+	}
+
+	return cfg.wordList.Replace(rules)
+}
+
+// runWordListReloader reloads the word list every interval until ctx is
+// cancelled, picking up edits to the file and rules changed by other server
+// instances. A list that fails to load leaves the previous one in place.
+func (cfg *apiConfig) runWordListReloader(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := cfg.reloadWordList(ctx)
+		if err != nil {
+			log.Printf("Error reloading moderation word list: %s", err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Why a chirp or user was reported. The reports table checks the same list.
+var reportReasons = []string{
+	"spam",
+	"harassment",
+	"hate",
+	"violence",
+	"self_harm",
+	"sexual",
+	"impersonation",
+	"misinformation",
+	"other",
+}
+
+const maxReportDetailsLength = 500
+
+// How a moderator can resolve a report.
+const (
+	resolutionDismiss     = "dismiss"
+	resolutionRemoveChirp = "remove_chirp"
+	resolutionSuspendUser = "suspend_user"
+)
+
+func parseReportReason(reason string) (string, error) {
+	if !slices.Contains(reportReasons, reason) {
+		return "", fmt.Errorf("reason must be one of %s", strings.Join(reportReasons, ", "))
+	}
+	return reason, nil
+}
+
+func validateReportDetails(details string) error {
+	if utf8.RuneCountInString(details) > maxReportDetailsLength {
+		return fmt.Errorf("details can be at most %d characters", maxReportDetailsLength)
+	}
+	return nil
+}
+
+func parseResolution(action string) (string, error) {
+	switch action {
+	case resolutionDismiss, resolutionRemoveChirp, resolutionSuspendUser:
+		return action, nil
+	}
+	return "", errors.New("action must be dismiss, remove_chirp or suspend_user")
+}
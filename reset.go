@@ -3,18 +3,54 @@ package main
 import "net/http"
 
 func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
+	type counts struct {
+		FileServerHits int32 `json:"file_server_hits"`
+	}
+
 	if cfg.platform != "dev" {
 		w.WriteHeader(http.StatusForbidden)
 		w.Write([]byte("Reset is only allowed in dev environment."))
 		return
 	}
-	cfg.fileserverHits.Store(0)
-	err := cfg.db.ResetUsers(r.Context())
+
+	tx, err := cfg.dbConn.BeginTx(r.Context(), nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Failed to reset the database: " + err.Error()))
+		return
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	err = qtx.ResetUsers(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Failed to reset the database: " + err.Error()))
+		return
+	}
+
+	// The audit log outlives the users it mentions, so a reset is recorded too.
+	err = recordAudit(r.Context(), qtx, auditEntry{
+		Action:     auditActionReset,
+		TargetType: auditTargetSystem,
+		Before:     counts{FileServerHits: cfg.fileserverHits.Load()},
+		After:      counts{},
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Failed to record the reset: " + err.Error()))
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("Failed to reset the database: " + err.Error()))
 		return
 	}
+
+	cfg.fileserverHits.Store(0)
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Hits reset to 0"))
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	schedulerInterval  = 15 * time.Second
+	schedulerBatchSize = 100
+)
+
+// runScheduler publishes due chirps every interval until ctx is cancelled.
+// Pending chirps live in the database, so anything that came due while the
+// server was down is published on the first tick after a restart.
+func (cfg *apiConfig) runScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := cfg.publishDueChirps(ctx)
+		if err != nil {
+			log.Printf("Error publishing scheduled chirps: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDueChirps publishes every chirp whose publish time has passed, in
+// batches. Each batch is claimed with SKIP LOCKED, so several server
+// instances can run the scheduler without publishing a chirp twice.
+func (cfg *apiConfig) publishDueChirps(ctx context.Context) error {
+	for {
+		published, err := cfg.publishDueBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if published < schedulerBatchSize {
+			return nil
+		}
+	}
+}
+
+func (cfg *apiConfig) publishDueBatch(ctx context.Context) (int, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.db.WithTx(tx)
+
+	// Published chirps take the current time as created_at so they land at
+	// the head of feeds rather than behind cursors readers already hold.
+	chirps, err := qtx.PublishDueChirps(ctx, schedulerBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, chirp := range chirps {
+		err = indexChirpBody(ctx, qtx, chirp)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(chirps), tx.Commit()
+}
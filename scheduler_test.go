@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+)
+
+// testTimeZone is far enough from UTC, and oddly enough offset, that times
+// read in the wrong zone are obviously wrong.
+const testTimeZone = "Pacific/Chatham"
+
+func TestPublishDueChirpsIgnoresSessionTimeZone(t *testing.T) {
+	cfg := newTestConfigInTimeZone(t, testTimeZone)
+
+	_, authorToken := createTestUser(t, cfg, "", auth.RoleUser)
+
+	later := createTestChirp(t, cfg, authorToken, map[string]any{
+		"body":       "see you in an hour",
+		"publish_at": time.Now().Add(time.Hour),
+	})
+	due := createTestChirp(t, cfg, authorToken, map[string]any{
+		"body":       "see you soon",
+		"publish_at": time.Now().Add(time.Hour),
+	})
+	_, err := cfg.dbConn.ExecContext(t.Context(), "UPDATE chirps SET scheduled_for = NOW() - interval '1 minute' WHERE id = $1", due.ID)
+	if err != nil {
+		t.Fatalf("making chirp due: %v", err)
+	}
+
+	err = cfg.publishDueChirps(t.Context())
+	if err != nil {
+		t.Fatalf("publishing due chirps: %v", err)
+	}
+
+	getChirp := cfg.middlewareOptionalAuth(cfg.handlerGetChirp)
+	expectStatus(t, serveTestRequest(t, "GET /api/chirps/{chirpID}", getChirp, "/api/chirps/"+due.ID.String(), "", nil), http.StatusOK, nil)
+	expectStatus(t, serveTestRequest(t, "GET /api/chirps/{chirpID}", getChirp, "/api/chirps/"+later.ID.String(), "", nil), http.StatusNotFound, nil)
+}
@@ -0,0 +1,16 @@
+-- name: UpdateChirpBody :one
+WITH revision AS (
+	INSERT INTO chirp_revisions (id, chirp_id, body, created_at)
+	SELECT gen_random_uuid(), chirps.id, chirps.body, chirps.updated_at FROM chirps
+	WHERE chirps.id = sqlc.arg('id') AND chirps.user_id = sqlc.arg('user_id')
+)
+UPDATE chirps SET body = sqlc.arg('body'),
+edited_at = NOW(),
+updated_at = NOW()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
+
+-- name: GetChirpRevisions :many
+SELECT * FROM chirp_revisions
+WHERE chirp_id = $1
+ORDER BY created_at;
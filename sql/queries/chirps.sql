@@ -1,27 +1,167 @@
 -- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, in_reply_to, rechirp_of, quote_of, scheduled_for, visibility)
 VALUES (
 	gen_random_uuid(),
 	NOW(),
 	NOW(),
 	$1,
-	$2
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7
 )
 RETURNING *;
 
--- name: GetChirps :many
+-- name: GetChirpsAsc :many
 SELECT * FROM chirps
-ORDER BY created_at;
+WHERE deleted_at IS NULL
+AND scheduled_for IS NULL
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id')::uuid)
+AND (
+	sqlc.narg('cursor_created_at')::timestamp IS NULL
+	OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND chirp_visible_to(id, user_id, visibility, sqlc.narg('viewer_id'))
+AND NOT chirp_muted_for(id, user_id, search_vector, sqlc.narg('viewer_id'))
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
 
--- name: GetChirpsByUser :many
+-- name: GetChirpsDesc :many
 SELECT * FROM chirps
-WHERE user_id=$1
-ORDER BY created_at;
+WHERE deleted_at IS NULL
+AND scheduled_for IS NULL
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id')::uuid)
+AND (
+	sqlc.narg('cursor_created_at')::timestamp IS NULL
+	OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND chirp_visible_to(id, user_id, visibility, sqlc.narg('viewer_id'))
+AND NOT chirp_muted_for(id, user_id, search_vector, sqlc.narg('viewer_id'))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetChirp :one
 SELECT * FROM chirps
-WHERE id=$1;
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL AND scheduled_for IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.narg('viewer_id'));
+
+-- name: GetChirpsByIDs :many
+SELECT * FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND deleted_at IS NULL
+AND scheduled_for IS NULL
+AND chirp_visible_to(id, user_id, visibility, sqlc.narg('viewer_id'));
+
+-- name: ChirpHasReplies :one
+SELECT EXISTS (
+	SELECT 1 FROM chirps
+	WHERE in_reply_to = $1
+);
 
 -- name: DeleteChirp :exec
 DELETE from chirps
 WHERE id=$1 AND user_id=$2;
+
+-- name: TombstoneChirp :exec
+WITH owned AS (
+	SELECT chirps.id FROM chirps
+	WHERE chirps.id = $1 AND chirps.user_id = $2
+),
+cleared_hashtags AS (
+	DELETE FROM chirp_hashtags
+	WHERE chirp_hashtags.chirp_id IN (SELECT owned.id FROM owned)
+),
+cleared_mentions AS (
+	DELETE FROM chirp_mentions
+	WHERE chirp_mentions.chirp_id IN (SELECT owned.id FROM owned)
+),
+cleared_poll AS (
+	DELETE FROM polls
+	WHERE polls.chirp_id IN (SELECT owned.id FROM owned)
+)
+UPDATE chirps SET body = '',
+deleted_at = NOW(),
+updated_at = NOW()
+WHERE id=$1 AND user_id=$2;
+
+-- name: GetThread :many
+WITH RECURSIVE ancestors AS (
+	SELECT chirps.id, chirps.in_reply_to FROM chirps
+	WHERE chirps.id = sqlc.arg('chirp_id')
+	UNION ALL
+	SELECT chirps.id, chirps.in_reply_to FROM chirps
+	JOIN ancestors ON chirps.id = ancestors.in_reply_to
+),
+thread AS (
+	SELECT chirps.id, 0 AS depth FROM chirps
+	WHERE chirps.id = (SELECT ancestors.id FROM ancestors WHERE ancestors.in_reply_to IS NULL)
+	UNION ALL
+	SELECT chirps.id, thread.depth + 1 FROM chirps
+	JOIN thread ON chirps.in_reply_to = thread.id
+)
+SELECT sqlc.embed(chirps), thread.depth::int AS depth
+FROM thread
+JOIN chirps ON chirps.id = thread.id
+WHERE chirps.scheduled_for IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.narg('viewer_id'))
+ORDER BY thread.depth, chirps.created_at, chirps.id;
+
+-- name: SearchChirps :many
+SELECT sqlc.embed(chirps),
+	ts_rank(search_vector, to_tsquery('english', sqlc.arg('query')::text))::real AS rank,
+	ts_headline(
+		'english',
+		replace(replace(replace(replace(replace(body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'),
+		to_tsquery('english', sqlc.arg('query')::text),
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20'
+	)::text AS snippet
+FROM chirps
+WHERE deleted_at IS NULL
+AND scheduled_for IS NULL
+AND search_vector @@ to_tsquery('english', sqlc.arg('query')::text)
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id')::uuid)
+AND chirp_visible_to(id, user_id, visibility, sqlc.narg('viewer_id'))
+AND NOT chirp_muted_for(id, user_id, search_vector, sqlc.narg('viewer_id'))
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetScheduledChirps :many
+SELECT * FROM chirps
+WHERE user_id = $1
+AND scheduled_for IS NOT NULL
+ORDER BY scheduled_for ASC, id ASC;
+
+-- name: GetScheduledChirp :one
+SELECT * FROM chirps
+WHERE id = $1 AND scheduled_for IS NOT NULL;
+
+-- name: RescheduleChirp :one
+UPDATE chirps SET scheduled_for = $2,
+updated_at = NOW()
+WHERE id = $1 AND scheduled_for IS NOT NULL
+RETURNING *;
+
+-- name: DeleteScheduledChirp :execrows
+DELETE FROM chirps
+WHERE id = $1 AND scheduled_for IS NOT NULL;
+
+-- name: GetChirpViewers :many
+SELECT viewer_id::uuid FROM chirps
+CROSS JOIN unnest(sqlc.arg('user_ids')::uuid[]) AS viewer_id
+WHERE chirps.id = sqlc.arg('chirp_id')
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, viewer_id);
+
+-- name: PublishDueChirps :many
+UPDATE chirps SET scheduled_for = NULL,
+created_at = NOW(),
+updated_at = NOW()
+WHERE id IN (
+	SELECT due.id FROM chirps AS due
+	WHERE due.scheduled_for <= NOW()
+	ORDER BY due.scheduled_for
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
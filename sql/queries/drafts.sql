@@ -0,0 +1,48 @@
+-- name: CreateDraft :one
+INSERT INTO drafts (id, created_at, updated_at, user_id, body, in_reply_to, quote_of, media_ids, visibility, publish_at, poll_options, poll_closes_at)
+VALUES (
+	gen_random_uuid(),
+	NOW(),
+	NOW(),
+	sqlc.arg('user_id'),
+	sqlc.arg('body'),
+	sqlc.narg('in_reply_to'),
+	sqlc.narg('quote_of'),
+	sqlc.arg('media_ids')::uuid[],
+	sqlc.arg('visibility'),
+	sqlc.narg('publish_at'),
+	sqlc.arg('poll_options')::text[],
+	sqlc.narg('poll_closes_at')
+)
+RETURNING *;
+
+-- name: GetDraft :one
+SELECT * FROM drafts
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetDrafts :many
+SELECT * FROM drafts
+WHERE user_id = sqlc.arg('user_id')
+AND (
+	sqlc.narg('cursor_updated_at')::timestamp IS NULL
+	OR (updated_at, id) < (sqlc.narg('cursor_updated_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateDraft :one
+UPDATE drafts SET body = sqlc.arg('body'),
+in_reply_to = sqlc.narg('in_reply_to'),
+quote_of = sqlc.narg('quote_of'),
+media_ids = sqlc.arg('media_ids')::uuid[],
+visibility = sqlc.arg('visibility'),
+publish_at = sqlc.narg('publish_at'),
+poll_options = sqlc.arg('poll_options')::text[],
+poll_closes_at = sqlc.narg('poll_closes_at'),
+updated_at = NOW()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id')
+RETURNING *;
+
+-- name: DeleteDraft :execrows
+DELETE FROM drafts
+WHERE id = $1 AND user_id = $2;
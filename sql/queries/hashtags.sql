@@ -0,0 +1,41 @@
+-- name: AddChirpHashtags :exec
+WITH tags AS (
+	INSERT INTO hashtags (id, tag, created_at)
+	SELECT gen_random_uuid(), tag, NOW() FROM unnest(sqlc.arg('tags')::text[]) AS tag
+	ON CONFLICT (tag) DO UPDATE SET tag = EXCLUDED.tag
+	RETURNING id
+)
+INSERT INTO chirp_hashtags (chirp_id, hashtag_id, created_at)
+SELECT chirps.id, tags.id, chirps.created_at FROM tags
+JOIN chirps ON chirps.id = sqlc.arg('chirp_id')
+ON CONFLICT DO NOTHING;
+
+-- name: ClearChirpHashtags :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = $1;
+
+-- name: GetChirpsByHashtag :many
+SELECT chirps.* FROM chirps
+JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+WHERE hashtags.tag = sqlc.arg('tag')
+AND chirps.deleted_at IS NULL
+AND chirps.scheduled_for IS NULL
+AND (
+	sqlc.narg('cursor_created_at')::timestamp IS NULL
+	OR (chirps.created_at, chirps.id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.narg('viewer_id'))
+AND NOT chirp_muted_for(chirps.id, chirps.user_id, chirps.search_vector, sqlc.narg('viewer_id'))
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetTrendingHashtags :many
+SELECT hashtags.tag, COUNT(*) AS chirp_count FROM chirp_hashtags
+JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.created_at > sqlc.arg('since')
+AND chirps.visibility = 'public'
+GROUP BY hashtags.tag
+ORDER BY chirp_count DESC, hashtags.tag
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,46 @@
+-- name: CreateMedia :one
+INSERT INTO media (id, created_at, user_id, content_type, blob_key, thumbnail_key, width, height, size_bytes)
+VALUES (
+	$1,
+	NOW(),
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8
+)
+RETURNING *;
+
+-- name: AttachMedia :execrows
+UPDATE media SET chirp_id = sqlc.arg('chirp_id'),
+position = array_position(sqlc.arg('ids')::uuid[], id)
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND user_id = sqlc.arg('user_id')
+AND chirp_id IS NULL;
+
+-- name: GetMediaForChirps :many
+SELECT * FROM media
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_id, position;
+
+-- name: DeleteChirpMedia :many
+DELETE FROM media
+WHERE chirp_id = $1
+RETURNING blob_key, thumbnail_key;
+
+-- name: GetViewableMedia :one
+SELECT COALESCE(chirps.visibility = 'public' AND chirps.scheduled_for IS NULL, false)::boolean AS is_public
+FROM media
+LEFT JOIN chirps ON chirps.id = media.chirp_id
+WHERE (media.blob_key = sqlc.arg('key') OR media.thumbnail_key = sqlc.arg('key'))
+AND (
+	media.user_id = sqlc.narg('viewer_id')
+	OR (
+		chirps.id IS NOT NULL
+		AND chirps.deleted_at IS NULL
+		AND chirps.scheduled_for IS NULL
+		AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.narg('viewer_id'))
+	)
+);
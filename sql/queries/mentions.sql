@@ -0,0 +1,22 @@
+-- name: AddChirpMentions :many
+INSERT INTO chirp_mentions (chirp_id, user_id)
+SELECT chirps.id, users.id FROM users
+JOIN chirps ON chirps.id = sqlc.arg('chirp_id')
+WHERE users.handle = ANY(sqlc.arg('handles')::text[])
+AND NOT EXISTS (
+	SELECT 1 FROM blocks
+	WHERE (blocks.blocker_id = users.id AND blocks.blocked_id = chirps.user_id)
+	OR (blocks.blocker_id = chirps.user_id AND blocks.blocked_id = users.id)
+)
+ON CONFLICT DO NOTHING
+RETURNING user_id;
+
+-- name: ClearChirpMentions :exec
+DELETE FROM chirp_mentions
+WHERE chirp_id = $1;
+
+-- name: GetChirpMentions :many
+SELECT chirp_mentions.chirp_id, users.id AS user_id, users.handle FROM chirp_mentions
+JOIN users ON users.id = chirp_mentions.user_id
+WHERE chirp_mentions.chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY users.handle;
@@ -0,0 +1,31 @@
+-- name: GetModerationRules :many
+SELECT * FROM moderation_rules
+ORDER BY word;
+
+-- name: GetModerationRule :one
+SELECT * FROM moderation_rules
+WHERE word = $1;
+
+-- name: UpsertModerationRule :one
+INSERT INTO moderation_rules (word, action, created_at, updated_at)
+VALUES ($1, $2, NOW(), NOW())
+ON CONFLICT (word) DO UPDATE SET action = EXCLUDED.action, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteModerationRule :execrows
+DELETE FROM moderation_rules
+WHERE word = $1;
+
+-- name: FlagChirp :exec
+INSERT INTO moderation_flags (id, created_at, chirp_id, words)
+VALUES (gen_random_uuid(), NOW(), $1, $2);
+
+-- name: GetModerationFlags :many
+SELECT moderation_flags.*, chirps.user_id, chirps.body FROM moderation_flags
+JOIN chirps ON chirps.id = moderation_flags.chirp_id
+WHERE (
+	sqlc.narg('cursor_created_at')::timestamp IS NULL
+	OR (moderation_flags.created_at, moderation_flags.id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY moderation_flags.created_at DESC, moderation_flags.id DESC
+LIMIT sqlc.arg('limit');
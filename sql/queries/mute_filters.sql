@@ -0,0 +1,18 @@
+-- name: CreateMuteFilter :one
+INSERT INTO mute_filters (id, created_at, user_id, kind, value, expires_at)
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, $4)
+ON CONFLICT (user_id, kind, value) DO UPDATE SET expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: GetMuteFilters :many
+SELECT * FROM mute_filters
+WHERE user_id = $1
+AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at, id;
+
+-- name: DeleteMuteFilter :execrows
+DELETE FROM mute_filters
+WHERE id = $1 AND user_id = $2;
+
+-- name: MuteFilterQueryIsEmpty :one
+SELECT numnode(phraseto_tsquery('english', sqlc.arg('value')::text)) = 0 AS empty;
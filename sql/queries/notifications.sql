@@ -0,0 +1,23 @@
+-- name: CreateNotifications :exec
+INSERT INTO notifications (id, created_at, user_id, actor_id, chirp_id, kind)
+SELECT gen_random_uuid(), NOW(), recipient, sqlc.arg('actor_id'), sqlc.arg('chirp_id'), sqlc.arg('kind')
+FROM unnest(sqlc.arg('user_ids')::uuid[]) AS recipient
+ON CONFLICT DO NOTHING;
+
+-- name: GetNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg('user_id')
+AND (
+	sqlc.narg('cursor_created_at')::timestamp IS NULL
+	OR (created_at, id) < (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+AND NOT EXISTS (
+	SELECT 1 FROM mutes
+	WHERE mutes.muter_id = notifications.user_id AND mutes.muted_id = notifications.actor_id
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: MarkNotificationsRead :exec
+UPDATE notifications SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
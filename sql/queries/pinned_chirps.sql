@@ -0,0 +1,24 @@
+-- name: PinChirp :execrows
+INSERT INTO pinned_chirps (user_id, chirp_id, created_at)
+SELECT sqlc.arg('user_id'), sqlc.arg('chirp_id'), NOW()
+WHERE (
+	SELECT COUNT(*) FROM pinned_chirps
+	JOIN chirps ON chirps.id = pinned_chirps.chirp_id
+	WHERE pinned_chirps.user_id = sqlc.arg('user_id')
+	AND chirps.deleted_at IS NULL
+) < sqlc.arg('max_pins')::int
+ON CONFLICT DO NOTHING;
+
+-- name: UnpinChirp :exec
+DELETE FROM pinned_chirps
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: GetPinnedChirps :many
+SELECT chirps.* FROM chirps
+JOIN pinned_chirps ON pinned_chirps.chirp_id = chirps.id
+WHERE pinned_chirps.user_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND chirps.scheduled_for IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.narg('viewer_id'))
+AND NOT chirp_muted_for(chirps.id, chirps.user_id, chirps.search_vector, sqlc.narg('viewer_id'))
+ORDER BY pinned_chirps.created_at DESC;
@@ -0,0 +1,43 @@
+-- name: CreatePoll :exec
+INSERT INTO polls (chirp_id, created_at, closes_at)
+VALUES ($1, NOW(), $2);
+
+-- name: CreatePollOptions :exec
+INSERT INTO poll_options (id, chirp_id, position, label)
+SELECT gen_random_uuid(), sqlc.arg('chirp_id'), label.position, label.label
+FROM unnest(sqlc.arg('labels')::text[]) WITH ORDINALITY AS label(label, position);
+
+-- name: GetPoll :one
+SELECT * FROM polls
+WHERE chirp_id = $1;
+
+-- name: GetPollOptionsForChirps :many
+SELECT poll_options.*, polls.closes_at FROM poll_options
+JOIN polls ON polls.chirp_id = poll_options.chirp_id
+WHERE poll_options.chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY poll_options.chirp_id, poll_options.position;
+
+-- name: GetPollVotes :many
+SELECT chirp_id, option_id FROM poll_votes
+WHERE user_id = sqlc.arg('user_id')
+AND chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: CreatePollVote :execrows
+INSERT INTO poll_votes (chirp_id, user_id, option_id, created_at)
+SELECT poll_options.chirp_id, sqlc.arg('user_id'), poll_options.id, NOW()
+FROM poll_options
+JOIN polls ON polls.chirp_id = poll_options.chirp_id
+WHERE poll_options.id = sqlc.arg('option_id')
+AND poll_options.chirp_id = sqlc.arg('chirp_id')
+AND polls.closes_at > NOW();
+
+-- name: LockPoll :exec
+SELECT chirp_id FROM polls
+WHERE chirp_id = $1
+FOR UPDATE;
+
+-- name: PollHasVotes :one
+SELECT EXISTS (
+	SELECT 1 FROM poll_votes
+	WHERE chirp_id = $1
+);
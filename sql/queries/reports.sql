@@ -0,0 +1,44 @@
+-- name: CreateReport :one
+INSERT INTO reports (id, created_at, reporter_id, user_id, chirp_id, reason, details)
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ChirpHasReports :one
+SELECT EXISTS (
+	SELECT 1 FROM reports
+	WHERE chirp_id = $1
+);
+
+-- name: GetReportForUpdate :one
+SELECT * FROM reports
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetReports :many
+SELECT * FROM reports
+WHERE (resolved_at IS NOT NULL) = sqlc.arg('resolved')::boolean
+AND (
+	sqlc.narg('cursor_created_at')::timestamp IS NULL
+	OR (created_at, id) > (sqlc.narg('cursor_created_at')::timestamp, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ResolveReports :many
+UPDATE reports SET resolved_at = NOW(), resolution = sqlc.arg('resolution')
+WHERE resolved_at IS NULL
+AND (
+	id = sqlc.arg('id')
+	OR chirp_id = sqlc.narg('chirp_id')
+	OR user_id = sqlc.narg('user_id')
+)
+RETURNING id;
+
+-- name: CreateReportActions :exec
+INSERT INTO report_actions (id, created_at, report_id, action, note, removed_body)
+SELECT gen_random_uuid(), NOW(), unnest(sqlc.arg('report_ids')::uuid[]), sqlc.arg('action'), sqlc.arg('note'), sqlc.arg('removed_body');
+
+-- name: GetReportActions :many
+SELECT * FROM report_actions
+WHERE report_id = ANY(sqlc.arg('report_ids')::uuid[])
+ORDER BY created_at;
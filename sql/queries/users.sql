@@ -1,11 +1,12 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 VALUES (
 	gen_random_uuid(),
 	NOW(),
 	NOW(),
 	$1,
-	$2
+	$2,
+	$3
 )
 RETURNING *; 
 
@@ -14,13 +15,88 @@ SELECT * FROM users
 WHERE email = $1;
 
 -- name: UpdateUsernamePassword :one
-UPDATE users SET email = $1,
-hashed_password = $2,
+UPDATE users SET email = sqlc.arg('email'),
+hashed_password = sqlc.arg('hashed_password'),
+handle = COALESCE(sqlc.narg('handle'), handle),
+display_name = COALESCE(sqlc.narg('display_name'), display_name),
+bio = COALESCE(sqlc.narg('bio'), bio),
+avatar_url = COALESCE(sqlc.narg('avatar_url'), avatar_url),
 updated_at = NOW()
-WHERE id=$3
+WHERE id=sqlc.arg('id')
 RETURNING *;
 
 -- name: UpgradeUser :exec
 UPDATE users SET is_chirpy_red = true
 WHERE id=$1
 RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: GetUserByHandle :one
+SELECT * FROM users
+WHERE handle = $1;
+
+-- name: GetUserProfile :one
+SELECT
+	users.id,
+	users.created_at,
+	users.handle,
+	users.display_name,
+	users.bio,
+	users.avatar_url,
+	users.is_chirpy_red,
+	(SELECT COUNT(*) FROM follows WHERE follows.followee_id = users.id) AS follower_count,
+	(SELECT COUNT(*) FROM follows WHERE follows.follower_id = users.id) AS following_count,
+	(
+		SELECT COUNT(*) FROM chirps
+		WHERE chirps.user_id = users.id
+		AND chirps.deleted_at IS NULL
+		AND chirps.scheduled_for IS NULL
+		AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.narg('viewer_id'))
+	) AS chirp_count
+FROM users
+WHERE users.id = sqlc.arg('id');
+
+-- name: SuspendUser :one
+UPDATE users SET suspended_at = NOW(),
+suspended_until = $2,
+suspension_reason = $3,
+updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UnsuspendUser :one
+UPDATE users SET suspended_at = NULL,
+suspended_until = NULL,
+suspension_reason = '',
+updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetActiveSuspension :one
+SELECT suspended_until, suspension_reason FROM users
+WHERE id = $1
+AND suspended_at IS NOT NULL
+AND (suspended_until IS NULL OR suspended_until > NOW());
+
+-- name: GetUserRole :one
+SELECT role FROM users
+WHERE id = $1;
+
+-- name: LockUser :exec
+SELECT id FROM users
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetUserRole :one
+UPDATE users SET role = $2,
+updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: AdminExists :one
+SELECT EXISTS (
+	SELECT 1 FROM users WHERE role = 'admin'
+);
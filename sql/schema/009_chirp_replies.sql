@@ -0,0 +1,33 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN in_reply_to UUID REFERENCES chirps ON DELETE SET NULL,
+ADD COLUMN reply_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX chirps_in_reply_to_idx ON chirps (in_reply_to);
+
+-- +goose StatementBegin
+CREATE FUNCTION chirps_update_reply_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' AND NEW.in_reply_to IS NOT NULL THEN
+		UPDATE chirps SET reply_count = reply_count + 1 WHERE id = NEW.in_reply_to;
+	ELSIF TG_OP = 'DELETE' AND OLD.in_reply_to IS NOT NULL THEN
+		UPDATE chirps SET reply_count = reply_count - 1 WHERE id = OLD.in_reply_to;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chirps_reply_count
+AFTER INSERT OR DELETE ON chirps
+FOR EACH ROW EXECUTE FUNCTION chirps_update_reply_count();
+
+-- +goose Down
+DROP TRIGGER chirps_reply_count ON chirps;
+DROP FUNCTION chirps_update_reply_count;
+
+ALTER TABLE chirps
+DROP COLUMN deleted_at,
+DROP COLUMN reply_count,
+DROP COLUMN in_reply_to;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE chirp_likes (
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	chirp_id UUID NOT NULL REFERENCES chirps ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL,
+	UNIQUE (user_id, chirp_id)
+);
+
+CREATE INDEX chirp_likes_user_id_created_at_idx ON chirp_likes (user_id, created_at);
+
+ALTER TABLE chirps
+ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementBegin
+CREATE FUNCTION chirps_update_like_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		UPDATE chirps SET like_count = like_count + 1 WHERE id = NEW.chirp_id;
+	ELSIF TG_OP = 'DELETE' THEN
+		UPDATE chirps SET like_count = like_count - 1 WHERE id = OLD.chirp_id;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chirp_likes_like_count
+AFTER INSERT OR DELETE ON chirp_likes
+FOR EACH ROW EXECUTE FUNCTION chirps_update_like_count();
+
+-- +goose Down
+DROP TRIGGER chirp_likes_like_count ON chirp_likes;
+DROP FUNCTION chirps_update_like_count;
+
+ALTER TABLE chirps
+DROP COLUMN like_count;
+
+DROP TABLE chirp_likes;
@@ -0,0 +1,50 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN rechirp_of UUID REFERENCES chirps ON DELETE CASCADE,
+ADD COLUMN quote_of UUID REFERENCES chirps ON DELETE SET NULL,
+ADD COLUMN rechirp_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN quote_count INTEGER NOT NULL DEFAULT 0,
+ADD CONSTRAINT chirps_single_share CHECK (rechirp_of IS NULL OR quote_of IS NULL);
+
+CREATE UNIQUE INDEX chirps_user_id_rechirp_of_idx ON chirps (user_id, rechirp_of)
+WHERE rechirp_of IS NOT NULL;
+CREATE INDEX chirps_quote_of_idx ON chirps (quote_of);
+
+-- +goose StatementBegin
+CREATE FUNCTION chirps_update_share_counts() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		IF NEW.rechirp_of IS NOT NULL THEN
+			UPDATE chirps SET rechirp_count = rechirp_count + 1 WHERE id = NEW.rechirp_of;
+		ELSIF NEW.quote_of IS NOT NULL THEN
+			UPDATE chirps SET quote_count = quote_count + 1 WHERE id = NEW.quote_of;
+		END IF;
+	ELSIF TG_OP = 'DELETE' THEN
+		IF OLD.rechirp_of IS NOT NULL THEN
+			UPDATE chirps SET rechirp_count = rechirp_count - 1 WHERE id = OLD.rechirp_of;
+		ELSIF OLD.quote_of IS NOT NULL THEN
+			UPDATE chirps SET quote_count = quote_count - 1 WHERE id = OLD.quote_of;
+		END IF;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chirps_share_counts
+AFTER INSERT OR DELETE ON chirps
+FOR EACH ROW EXECUTE FUNCTION chirps_update_share_counts();
+
+-- +goose Down
+DROP TRIGGER chirps_share_counts ON chirps;
+DROP FUNCTION chirps_update_share_counts;
+
+DROP INDEX chirps_quote_of_idx;
+DROP INDEX chirps_user_id_rechirp_of_idx;
+
+ALTER TABLE chirps
+DROP CONSTRAINT chirps_single_share,
+DROP COLUMN quote_count,
+DROP COLUMN rechirp_count,
+DROP COLUMN quote_of,
+DROP COLUMN rechirp_of;
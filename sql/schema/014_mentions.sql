@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN handle TEXT UNIQUE;
+
+CREATE TABLE chirp_mentions (
+	chirp_id UUID NOT NULL REFERENCES chirps ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	PRIMARY KEY (chirp_id, user_id)
+);
+
+CREATE TABLE notifications (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	actor_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	chirp_id UUID REFERENCES chirps ON DELETE CASCADE,
+	kind TEXT NOT NULL,
+	read_at TIMESTAMP,
+	UNIQUE (user_id, chirp_id, kind)
+);
+
+CREATE INDEX notifications_user_id_created_at_idx ON notifications (user_id, created_at);
+
+-- +goose Down
+DROP TABLE notifications;
+DROP TABLE chirp_mentions;
+
+ALTER TABLE users
+DROP COLUMN handle;
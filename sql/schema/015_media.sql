@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE media (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	chirp_id UUID REFERENCES chirps ON DELETE CASCADE,
+	position INTEGER,
+	content_type TEXT NOT NULL,
+	blob_key TEXT NOT NULL,
+	thumbnail_key TEXT NOT NULL,
+	width INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	size_bytes BIGINT NOT NULL
+);
+
+CREATE INDEX media_chirp_id_idx ON media (chirp_id, position);
+
+-- +goose Down
+DROP TABLE media;
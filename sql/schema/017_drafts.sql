@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE drafts (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	body TEXT NOT NULL DEFAULT '',
+	in_reply_to UUID REFERENCES chirps ON DELETE SET NULL,
+	quote_of UUID REFERENCES chirps ON DELETE SET NULL,
+	media_ids UUID[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX drafts_user_id_updated_at_idx ON drafts (user_id, updated_at DESC, id DESC);
+
+-- +goose Down
+DROP TABLE drafts;
@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE polls (
+	chirp_id UUID PRIMARY KEY REFERENCES chirps ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL,
+	closes_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE poll_options (
+	id UUID PRIMARY KEY,
+	chirp_id UUID NOT NULL REFERENCES polls ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	label TEXT NOT NULL,
+	vote_count INTEGER NOT NULL DEFAULT 0,
+	UNIQUE (chirp_id, position)
+);
+
+CREATE TABLE poll_votes (
+	chirp_id UUID NOT NULL REFERENCES polls ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	option_id UUID NOT NULL REFERENCES poll_options ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (chirp_id, user_id)
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION poll_options_update_vote_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.option_id;
+	ELSIF TG_OP = 'DELETE' THEN
+		UPDATE poll_options SET vote_count = vote_count - 1 WHERE id = OLD.option_id;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER poll_votes_vote_count
+AFTER INSERT OR DELETE ON poll_votes
+FOR EACH ROW EXECUTE FUNCTION poll_options_update_vote_count();
+
+-- +goose Down
+DROP TRIGGER poll_votes_vote_count ON poll_votes;
+DROP FUNCTION poll_options_update_vote_count;
+
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
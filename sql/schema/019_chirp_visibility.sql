@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+CHECK (visibility IN ('public', 'followers', 'mentioned'));
+
+-- chirp_visible_to reports whether viewer_id (NULL for anonymous readers)
+-- may read a chirp. Authors always see their own chirps.
+-- +goose StatementBegin
+CREATE FUNCTION chirp_visible_to(target_id UUID, target_author_id UUID, target_visibility TEXT, viewer_id UUID)
+RETURNS BOOLEAN AS $$
+	SELECT target_visibility = 'public'
+	OR target_author_id = viewer_id
+	OR (target_visibility = 'followers' AND EXISTS (
+		SELECT 1 FROM follows
+		WHERE follows.followee_id = target_author_id
+		AND follows.follower_id = viewer_id
+	))
+	OR (target_visibility = 'mentioned' AND EXISTS (
+		SELECT 1 FROM chirp_mentions
+		WHERE chirp_mentions.chirp_id = target_id
+		AND chirp_mentions.user_id = viewer_id
+	));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION chirp_visible_to;
+
+ALTER TABLE chirps
+DROP COLUMN visibility;
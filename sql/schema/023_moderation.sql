@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE moderation_rules (
+	word TEXT PRIMARY KEY,
+	action TEXT NOT NULL CHECK (action IN ('block', 'mask', 'flag')),
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+
+-- The words that used to be hardcoded in the profanity filter.
+INSERT INTO moderation_rules (word, action, created_at, updated_at)
+VALUES
+	('kerfuffle', 'mask', NOW(), NOW()),
+	('sharbert', 'mask', NOW(), NOW()),
+	('fornax', 'mask', NOW(), NOW());
+
+CREATE TABLE moderation_flags (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	chirp_id UUID NOT NULL REFERENCES chirps ON DELETE CASCADE,
+	words TEXT[] NOT NULL
+);
+
+CREATE INDEX moderation_flags_created_at_idx ON moderation_flags (created_at);
+
+-- +goose Down
+DROP TABLE moderation_flags;
+DROP TABLE moderation_rules;
@@ -0,0 +1,36 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN suspended_at TIMESTAMP;
+
+-- user_id is the reported user: the author for chirp reports.
+CREATE TABLE reports (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	reporter_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	chirp_id UUID REFERENCES chirps ON DELETE CASCADE,
+	reason TEXT NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate', 'violence', 'self_harm', 'sexual', 'impersonation', 'misinformation', 'other')),
+	details TEXT NOT NULL,
+	resolved_at TIMESTAMP,
+	resolution TEXT CHECK (resolution IN ('dismiss', 'remove_chirp', 'suspend_user'))
+);
+
+-- Each reporter can report a chirp, or a user, once.
+CREATE UNIQUE INDEX reports_reporter_id_chirp_id_idx ON reports (reporter_id, chirp_id) WHERE chirp_id IS NOT NULL;
+CREATE UNIQUE INDEX reports_reporter_id_user_id_idx ON reports (reporter_id, user_id) WHERE chirp_id IS NULL;
+CREATE INDEX reports_open_created_at_idx ON reports (created_at) WHERE resolved_at IS NULL;
+
+CREATE TABLE report_actions (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	report_id UUID NOT NULL REFERENCES reports ON DELETE CASCADE,
+	action TEXT NOT NULL,
+	note TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE report_actions;
+DROP TABLE reports;
+
+ALTER TABLE users
+DROP COLUMN suspended_at;
@@ -0,0 +1,66 @@
+-- +goose Up
+CREATE TABLE blocks (
+	blocker_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	blocked_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (blocker_id, blocked_id),
+	CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX blocks_blocked_id_idx ON blocks (blocked_id);
+
+CREATE TABLE mutes (
+	muter_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	muted_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (muter_id, muted_id),
+	CHECK (muter_id <> muted_id)
+);
+
+-- Blocking someone also hides your chirps from them.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(target_id UUID, target_author_id UUID, target_visibility TEXT, viewer_id UUID)
+RETURNS BOOLEAN AS $$
+	SELECT (
+		target_visibility = 'public'
+		OR target_author_id = viewer_id
+		OR (target_visibility = 'followers' AND EXISTS (
+			SELECT 1 FROM follows
+			WHERE follows.followee_id = target_author_id
+			AND follows.follower_id = viewer_id
+		))
+		OR (target_visibility = 'mentioned' AND EXISTS (
+			SELECT 1 FROM chirp_mentions
+			WHERE chirp_mentions.chirp_id = target_id
+			AND chirp_mentions.user_id = viewer_id
+		))
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM blocks
+		WHERE blocks.blocker_id = target_author_id
+		AND blocks.blocked_id = viewer_id
+	);
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(target_id UUID, target_author_id UUID, target_visibility TEXT, viewer_id UUID)
+RETURNS BOOLEAN AS $$
+	SELECT target_visibility = 'public'
+	OR target_author_id = viewer_id
+	OR (target_visibility = 'followers' AND EXISTS (
+		SELECT 1 FROM follows
+		WHERE follows.followee_id = target_author_id
+		AND follows.follower_id = viewer_id
+	))
+	OR (target_visibility = 'mentioned' AND EXISTS (
+		SELECT 1 FROM chirp_mentions
+		WHERE chirp_mentions.chirp_id = target_id
+		AND chirp_mentions.user_id = viewer_id
+	));
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+DROP TABLE mutes;
+DROP TABLE blocks;
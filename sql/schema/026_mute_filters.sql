@@ -0,0 +1,39 @@
+-- +goose Up
+-- query is the value compiled once into the form chirps' search vectors are
+-- matched against, so reads don't reparse every filter for every chirp.
+CREATE TABLE mute_filters (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	user_id UUID NOT NULL REFERENCES users ON DELETE CASCADE,
+	kind TEXT NOT NULL CHECK (kind IN ('word', 'phrase', 'hashtag')),
+	value TEXT NOT NULL,
+	expires_at TIMESTAMP,
+	query tsquery GENERATED ALWAYS AS (phraseto_tsquery('english', value)) STORED,
+	UNIQUE (user_id, kind, value)
+);
+
+-- chirp_muted_for reports whether a chirp matches one of viewer_id's
+-- unexpired mute filters. Nobody's filters hide their own chirps.
+-- +goose StatementBegin
+CREATE FUNCTION chirp_muted_for(target_id UUID, target_author_id UUID, target_search_vector tsvector, viewer_id UUID)
+RETURNS BOOLEAN AS $$
+	SELECT target_author_id IS DISTINCT FROM viewer_id AND EXISTS (
+		SELECT 1 FROM mute_filters
+		WHERE mute_filters.user_id = viewer_id
+		AND (mute_filters.expires_at IS NULL OR mute_filters.expires_at > NOW())
+		AND (
+			(mute_filters.kind = 'hashtag' AND EXISTS (
+				SELECT 1 FROM chirp_hashtags
+				JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+				WHERE chirp_hashtags.chirp_id = target_id
+				AND hashtags.tag = mute_filters.value
+			))
+			OR (mute_filters.kind <> 'hashtag' AND target_search_vector @@ mute_filters.query)
+		)
+	);
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION chirp_muted_for;
+DROP TABLE mute_filters;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- A suspension lasts until suspended_until, or indefinitely when it's NULL.
+ALTER TABLE users
+ADD COLUMN suspended_until TIMESTAMP,
+ADD COLUMN suspension_reason TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN suspension_reason,
+DROP COLUMN suspended_until;
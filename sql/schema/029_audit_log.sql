@@ -0,0 +1,34 @@
+-- +goose Up
+-- actor_id is NULL for actions taken by the system, such as payment
+-- webhooks. Actors and targets aren't foreign keys so entries outlive them.
+CREATE TABLE audit_log (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	actor_id UUID,
+	action TEXT NOT NULL,
+	target_type TEXT NOT NULL,
+	target_id TEXT NOT NULL,
+	before JSONB NOT NULL,
+	after JSONB NOT NULL,
+	request_id TEXT NOT NULL
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at, id);
+CREATE INDEX audit_log_actor_id_idx ON audit_log (actor_id, created_at);
+CREATE INDEX audit_log_target_idx ON audit_log (target_type, target_id, created_at);
+
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TABLE audit_log;
+DROP FUNCTION audit_log_append_only;
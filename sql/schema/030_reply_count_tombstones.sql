@@ -0,0 +1,57 @@
+-- +goose Up
+-- reply_count only counts live replies. Tombstoning a reply is an UPDATE,
+-- so the trigger has to watch deleted_at too, and deleting a tombstone
+-- later mustn't decrement the count a second time.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirps_update_reply_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' AND NEW.in_reply_to IS NOT NULL THEN
+		UPDATE chirps SET reply_count = reply_count + 1 WHERE id = NEW.in_reply_to;
+	ELSIF TG_OP = 'DELETE' AND OLD.in_reply_to IS NOT NULL AND OLD.deleted_at IS NULL THEN
+		UPDATE chirps SET reply_count = reply_count - 1 WHERE id = OLD.in_reply_to;
+	ELSIF TG_OP = 'UPDATE' AND NEW.in_reply_to IS NOT NULL
+		AND OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+		UPDATE chirps SET reply_count = reply_count - 1 WHERE id = NEW.in_reply_to;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER chirps_reply_count ON chirps;
+
+CREATE TRIGGER chirps_reply_count
+AFTER INSERT OR DELETE OR UPDATE OF deleted_at ON chirps
+FOR EACH ROW EXECUTE FUNCTION chirps_update_reply_count();
+
+-- Counts that drifted while tombstoned replies were still counted.
+UPDATE chirps SET reply_count = (
+	SELECT COUNT(*) FROM chirps AS replies
+	WHERE replies.in_reply_to = chirps.id
+	AND replies.deleted_at IS NULL
+);
+
+-- +goose Down
+DROP TRIGGER chirps_reply_count ON chirps;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirps_update_reply_count() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'INSERT' AND NEW.in_reply_to IS NOT NULL THEN
+		UPDATE chirps SET reply_count = reply_count + 1 WHERE id = NEW.in_reply_to;
+	ELSIF TG_OP = 'DELETE' AND OLD.in_reply_to IS NOT NULL THEN
+		UPDATE chirps SET reply_count = reply_count - 1 WHERE id = OLD.in_reply_to;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chirps_reply_count
+AFTER INSERT OR DELETE ON chirps
+FOR EACH ROW EXECUTE FUNCTION chirps_update_reply_count();
+
+UPDATE chirps SET reply_count = (
+	SELECT COUNT(*) FROM chirps AS replies
+	WHERE replies.in_reply_to = chirps.id
+);
@@ -0,0 +1,50 @@
+-- +goose Up
+-- Rechirps have no body of their own and quotes show the chirp they quote,
+-- so chirp_muted_for also matches the chirp they share.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_muted_for(target_id UUID, target_author_id UUID, target_search_vector tsvector, viewer_id UUID)
+RETURNS BOOLEAN AS $$
+	SELECT target_author_id IS DISTINCT FROM viewer_id AND EXISTS (
+		SELECT 1 FROM mute_filters
+		JOIN (
+			SELECT target_id AS id, target_search_vector AS search_vector
+			UNION ALL
+			SELECT shared.id, shared.search_vector FROM chirps
+			JOIN chirps AS shared ON shared.id IN (chirps.rechirp_of, chirps.quote_of)
+			WHERE chirps.id = target_id
+		) AS matched ON TRUE
+		WHERE mute_filters.user_id = viewer_id
+		AND (mute_filters.expires_at IS NULL OR mute_filters.expires_at > NOW())
+		AND (
+			(mute_filters.kind = 'hashtag' AND EXISTS (
+				SELECT 1 FROM chirp_hashtags
+				JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+				WHERE chirp_hashtags.chirp_id = matched.id
+				AND hashtags.tag = mute_filters.value
+			))
+			OR (mute_filters.kind <> 'hashtag' AND matched.search_vector @@ mute_filters.query)
+		)
+	);
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_muted_for(target_id UUID, target_author_id UUID, target_search_vector tsvector, viewer_id UUID)
+RETURNS BOOLEAN AS $$
+	SELECT target_author_id IS DISTINCT FROM viewer_id AND EXISTS (
+		SELECT 1 FROM mute_filters
+		WHERE mute_filters.user_id = viewer_id
+		AND (mute_filters.expires_at IS NULL OR mute_filters.expires_at > NOW())
+		AND (
+			(mute_filters.kind = 'hashtag' AND EXISTS (
+				SELECT 1 FROM chirp_hashtags
+				JOIN hashtags ON hashtags.id = chirp_hashtags.hashtag_id
+				WHERE chirp_hashtags.chirp_id = target_id
+				AND hashtags.tag = mute_filters.value
+			))
+			OR (mute_filters.kind <> 'hashtag' AND target_search_vector @@ mute_filters.query)
+		)
+	);
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- A draft has a poll when poll_closes_at is set.
+ALTER TABLE drafts
+ADD COLUMN visibility TEXT NOT NULL DEFAULT '',
+ADD COLUMN publish_at TIMESTAMP,
+ADD COLUMN poll_options TEXT[] NOT NULL DEFAULT '{}',
+ADD COLUMN poll_closes_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE drafts
+DROP COLUMN poll_closes_at,
+DROP COLUMN poll_options,
+DROP COLUMN publish_at,
+DROP COLUMN visibility;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- request_id is always generated by the server. client_request_id is the
+-- X-Request-ID a proxy sent, if any, kept only for correlating logs.
+ALTER TABLE audit_log
+ADD COLUMN client_request_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE audit_log
+DROP COLUMN client_request_id;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- scheduled_for is compared with NOW(), so it needs a zone to mean the same
+-- instant whatever the session time zone is. Existing values were written
+-- as UTC.
+ALTER TABLE chirps
+ALTER COLUMN scheduled_for TYPE TIMESTAMPTZ USING scheduled_for AT TIME ZONE 'UTC';
+
+-- +goose Down
+ALTER TABLE chirps
+ALTER COLUMN scheduled_for TYPE TIMESTAMP USING scheduled_for AT TIME ZONE 'UTC';
@@ -0,0 +1,9 @@
+-- +goose Up
+-- expires_at is compared with NOW(), so like scheduled_for it needs a zone.
+-- Existing values were written as UTC.
+ALTER TABLE mute_filters
+ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC';
+
+-- +goose Down
+ALTER TABLE mute_filters
+ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE 'UTC';
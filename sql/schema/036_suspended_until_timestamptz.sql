@@ -0,0 +1,9 @@
+-- +goose Up
+-- suspended_until is compared with NOW(), so like scheduled_for it needs a
+-- zone. Existing values were written as UTC.
+ALTER TABLE users
+ALTER COLUMN suspended_until TYPE TIMESTAMPTZ USING suspended_until AT TIME ZONE 'UTC';
+
+-- +goose Down
+ALTER TABLE users
+ALTER COLUMN suspended_until TYPE TIMESTAMP USING suspended_until AT TIME ZONE 'UTC';
@@ -0,0 +1,9 @@
+-- +goose Up
+-- closes_at is compared with NOW(), so like scheduled_for it needs a zone.
+-- Existing values were written as UTC.
+ALTER TABLE polls
+ALTER COLUMN closes_at TYPE TIMESTAMPTZ USING closes_at AT TIME ZONE 'UTC';
+
+-- +goose Down
+ALTER TABLE polls
+ALTER COLUMN closes_at TYPE TIMESTAMP USING closes_at AT TIME ZONE 'UTC';
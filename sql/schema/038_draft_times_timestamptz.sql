@@ -0,0 +1,11 @@
+-- +goose Up
+-- Drafts keep their publish and poll closing times in the same form the
+-- chirps and polls they become do. Existing values were written as UTC.
+ALTER TABLE drafts
+ALTER COLUMN publish_at TYPE TIMESTAMPTZ USING publish_at AT TIME ZONE 'UTC',
+ALTER COLUMN poll_closes_at TYPE TIMESTAMPTZ USING poll_closes_at AT TIME ZONE 'UTC';
+
+-- +goose Down
+ALTER TABLE drafts
+ALTER COLUMN poll_closes_at TYPE TIMESTAMP USING poll_closes_at AT TIME ZONE 'UTC',
+ALTER COLUMN publish_at TYPE TIMESTAMP USING publish_at AT TIME ZONE 'UTC';
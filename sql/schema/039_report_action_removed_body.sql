@@ -0,0 +1,9 @@
+-- +goose Up
+-- Removing a chirp clears its body, so remove_chirp actions keep a copy of
+-- what was removed for the resolved queue.
+ALTER TABLE report_actions
+ADD COLUMN removed_body TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE report_actions
+DROP COLUMN removed_body;
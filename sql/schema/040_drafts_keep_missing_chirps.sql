@@ -0,0 +1,20 @@
+-- +goose Up
+-- A draft keeps the IDs of the chirps it replies to or quotes even after
+-- they're deleted, so publishing it fails instead of quietly posting a
+-- standalone chirp.
+ALTER TABLE drafts
+DROP CONSTRAINT drafts_in_reply_to_fkey,
+DROP CONSTRAINT drafts_quote_of_fkey;
+
+-- +goose Down
+UPDATE drafts SET in_reply_to = NULL
+WHERE in_reply_to IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM chirps WHERE chirps.id = drafts.in_reply_to);
+
+UPDATE drafts SET quote_of = NULL
+WHERE quote_of IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM chirps WHERE chirps.id = drafts.quote_of);
+
+ALTER TABLE drafts
+ADD CONSTRAINT drafts_in_reply_to_fkey FOREIGN KEY (in_reply_to) REFERENCES chirps ON DELETE SET NULL,
+ADD CONSTRAINT drafts_quote_of_fkey FOREIGN KEY (quote_of) REFERENCES chirps ON DELETE SET NULL;
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JoeVinten/chirpy/internal/auth"
+	"github.com/JoeVinten/chirpy/internal/database"
+	"github.com/JoeVinten/chirpy/internal/media"
+	"github.com/JoeVinten/chirpy/internal/moderation"
+	"github.com/google/uuid"
+)
+
+// The handler tests run against a real database. Point CHIRPY_TEST_DB_URL
+// at a Postgres database they're allowed to create schemas in; each test
+// migrates a schema of its own and drops it afterwards. Without it they're
+// skipped.
+const testDBURLEnv = "CHIRPY_TEST_DB_URL"
+
+const testJWTSecret = "test-secret"
+
+// newTestConfig returns a config backed by a freshly migrated schema.
+func newTestConfig(t *testing.T) *apiConfig {
+	t.Helper()
+	return newTestConfigInTimeZone(t, "")
+}
+
+// newTestConfigInTimeZone is newTestConfig with the database session set to
+// zone, for checking times don't depend on it. An empty zone leaves the
+// server's default.
+func newTestConfigInTimeZone(t *testing.T, zone string) *apiConfig {
+	t.Helper()
+
+	dbURL := os.Getenv(testDBURLEnv)
+	if dbURL == "" {
+		t.Skipf("%s not set", testDBURLEnv)
+	}
+
+	admin, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		t.Fatalf("creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", testDBURLEnv, err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	if zone != "" {
+		query.Set("timezone", zone)
+	}
+	u.RawQuery = query.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrateTestDB(t, db)
+
+	blobStore, err := media.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("creating media store: %v", err)
+	}
+
+	cfg := &apiConfig{
+		db:                database.New(db),
+		dbConn:            db,
+		blobStore:         blobStore,
+		platform:          "dev",
+		jwtSecret:         testJWTSecret,
+		wordList:          &moderation.WordList{},
+		maxChirpLengthRed: defaultMaxChirpLengthRed,
+	}
+	cfg.moderator = moderation.Chain{cfg.wordList}
+	return cfg
+}
+
+// migrateTestDB runs the Up half of every migration in order, the way goose
+// would.
+func migrateTestDB(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("sql", "schema", "*.sql"))
+	if err != nil {
+		t.Fatalf("listing migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		dat, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("reading %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(dat), "-- +goose Down")
+		_, err = db.Exec(up)
+		if err != nil {
+			t.Fatalf("applying %s: %v", file, err)
+		}
+	}
+}
+
+// createTestUser adds a user with role and returns them with an access
+// token. handle can be empty.
+func createTestUser(t *testing.T, cfg *apiConfig, handle string, role auth.Role) (database.User, string) {
+	t.Helper()
+
+	user, err := cfg.db.CreateUser(t.Context(), database.CreateUserParams{
+		Email:          uuid.NewString() + "@example.com",
+		HashedPassword: "unused",
+		Handle:         sql.NullString{String: handle, Valid: handle != ""},
+	})
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	if role != auth.RoleUser {
+		user, err = cfg.db.SetUserRole(t.Context(), database.SetUserRoleParams{
+			ID:   user.ID,
+			Role: string(role),
+		})
+		if err != nil {
+			t.Fatalf("setting role: %v", err)
+		}
+	}
+
+	token, err := auth.MakeJWT(user.ID, role, testJWTSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("making token: %v", err)
+	}
+	return user, token
+}
+
+// serveTestRequest sends a request to handler registered at pattern, as the
+// user token belongs to. token and body can be empty.
+func serveTestRequest(t *testing.T, pattern string, handler http.HandlerFunc, target, token string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&reqBody).Encode(body)
+		if err != nil {
+			t.Fatalf("encoding body: %v", err)
+		}
+	}
+
+	method, _, _ := strings.Cut(pattern, " ")
+	req := httptest.NewRequest(method, target, &reqBody)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, handler)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// expectStatus fails the test unless rec has the wanted status, and decodes
+// its body into dst if dst isn't nil.
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, want int, dst any) {
+	t.Helper()
+
+	if rec.Code != want {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, want, rec.Body)
+	}
+	if dst != nil {
+		err := json.Unmarshal(rec.Body.Bytes(), dst)
+		if err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+	}
+}
+
+// createTestChirp posts a chirp through the API and returns it.
+func createTestChirp(t *testing.T, cfg *apiConfig, token string, params map[string]any) Chirp {
+	t.Helper()
+
+	rec := serveTestRequest(t, "POST /api/chirps", cfg.middlewareAuth(cfg.handlerCreateChirp), "/api/chirps", token, params)
+	var chirp Chirp
+	expectStatus(t, rec, http.StatusCreated, &chirp)
+	return chirp
+}
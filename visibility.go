@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Who can read a chirp. Authors can always read their own chirps; the
+// chirp_visible_to SQL function enforces the rest on every read query.
+const (
+	visibilityPublic    = "public"
+	visibilityFollowers = "followers"
+	visibilityMentioned = "mentioned"
+)
+
+var errNotShareable = errors.New("only public chirps can be rechirped or quoted")
+
+// parseVisibility checks a requested visibility, defaulting to public.
+func parseVisibility(visibility string) (string, error) {
+	switch visibility {
+	case "":
+		return visibilityPublic, nil
+	case visibilityPublic, visibilityFollowers, visibilityMentioned:
+		return visibility, nil
+	}
+	return "", fmt.Errorf("visibility must be %q, %q or %q", visibilityPublic, visibilityFollowers, visibilityMentioned)
+}